@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Exit codes of the process.
+const (
+	exitOK = 0
+	// exitConfigError means the configuration could not be loaded at startup or is invalid.
+	exitConfigError = 1
+	// exitUsage means the command line is invalid.
+	exitUsage = 2
+	// exitServerError means the server could not start or stopped without being shut down.
+	exitServerError = 3
+	// exitShutdownError means requests in progress did not finish within shutdownTimeout.
+	exitShutdownError = 4
+	// exitOutputError means rendered output could not be written or its hook failed.
+	exitOutputError = 5
+)
+
+// shutdownTimeout is how long requests in progress may take to finish on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// server is the part of presentation.Server the lifecycle manages.
+type server interface {
+	Start() error
+	Shutdown(ctx context.Context) error
+}
+
+// lifecycle runs a server, reloading its configuration on request and shutting it down gracefully.
+type lifecycle struct {
+	server server
+	reload func()
+	// reloads receives a value, such as SIGHUP, whenever the configuration should be reloaded.
+	reloads <-chan os.Signal
+	// shutdownTimeout is how long requests in progress may take to finish on shutdown.
+	shutdownTimeout time.Duration
+}
+
+// run starts the server and returns the exit code once it stopped:
+// after ctx is done and requests in progress finished, or when the server failed.
+func (l *lifecycle) run(ctx context.Context) int {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- l.server.Start()
+	}()
+
+	for {
+		select {
+		case err := <-errs:
+			if errors.Is(err, http.ErrServerClosed) {
+				err = errors.New("server closed unexpectedly")
+			}
+			slog.Error("server stopped", "error", err)
+			return exitServerError
+		case sig := <-l.reloads:
+			slog.Info("reloading configuration", "signal", sig)
+			l.reload()
+		case <-ctx.Done():
+			slog.Info("shutting down, waiting for requests in progress", "timeout", l.shutdownTimeout)
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+			defer cancel()
+			if err := l.server.Shutdown(shutdownCtx); err != nil {
+				slog.Error("failed to shut down gracefully", "error", err)
+				return exitShutdownError
+			}
+			slog.Info("server stopped")
+			return exitOK
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer serves until it is shut down, unless startErr is set.
+type fakeServer struct {
+	startErr error
+	// drain is how long requests in progress take to finish on shutdown.
+	drain    time.Duration
+	stopped  chan struct{}
+	shutdown atomic.Bool
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{stopped: make(chan struct{})}
+}
+
+func (s *fakeServer) Start() error {
+	if s.startErr != nil {
+		return s.startErr
+	}
+	<-s.stopped
+	return http.ErrServerClosed
+}
+
+func (s *fakeServer) Shutdown(ctx context.Context) error {
+	s.shutdown.Store(true)
+	defer close(s.stopped)
+	select {
+	case <-time.After(s.drain):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestLifecycle_run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		server           *fakeServer
+		timeout          time.Duration
+		expected         int
+		expectedShutdown bool
+	}{
+		{
+			name:             "should exit successfully after requests finished",
+			server:           newFakeServer(),
+			timeout:          time.Second,
+			expected:         exitOK,
+			expectedShutdown: true,
+		},
+		{
+			name:             "should report requests not finishing in time",
+			server:           &fakeServer{drain: time.Hour, stopped: make(chan struct{})},
+			timeout:          10 * time.Millisecond,
+			expected:         exitShutdownError,
+			expectedShutdown: true,
+		},
+		{
+			name:     "should report a server that failed to start",
+			server:   &fakeServer{startErr: errors.New("address already in use"), stopped: make(chan struct{})},
+			timeout:  time.Second,
+			expected: exitServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.server.startErr == nil {
+				cancel()
+			} else {
+				defer cancel()
+			}
+
+			l := &lifecycle{server: tt.server, reload: func() {}, shutdownTimeout: tt.timeout}
+			assert.Equal(t, tt.expected, l.run(ctx))
+			assert.Equal(t, tt.expectedShutdown, tt.server.shutdown.Load())
+		})
+	}
+}
+
+func TestLifecycle_run_Reload(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := make(chan os.Signal)
+	reloaded := make(chan struct{})
+	l := &lifecycle{
+		server:          newFakeServer(),
+		reload:          func() { reloaded <- struct{}{} },
+		reloads:         reloads,
+		shutdownTimeout: time.Second,
+	}
+
+	code := make(chan int)
+	go func() {
+		code <- l.run(ctx)
+	}()
+	for range 2 {
+		reloads <- syscall.SIGHUP
+		<-reloaded
+	}
+	cancel()
+	assert.Equal(t, exitOK, <-code)
+}
@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "time/tzdata" // Load timezone data
 
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
 	"github.com/alkshmir/sinkhole-detox/internal/infra/config"
 	"github.com/alkshmir/sinkhole-detox/internal/presentation"
 )
 
-var srv *presentation.Server
-
 func showVersion() {
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
@@ -31,34 +36,153 @@ func showVersion() {
 	slog.Info("Sinkhole-Detox", "GoVersion", info.GoVersion, "Version", info.Main.Version, "Commit", commit)
 }
 
-func init() {
-	showVersion()
-
-	configPath := "config/config.yaml"
+func configPath() string {
 	if envPath := os.Getenv("CONFIG_FILE_PATH"); envPath != "" {
-		configPath = envPath
+		return envPath
 	}
+	return "config/config.yaml"
+}
 
-	conf, err := config.LoadConfig(configPath)
-	if err != nil {
-		slog.Error("failed to load config", "error", err)
-		os.Exit(1)
+func newBlockerFactory(conf *config.Config) config.BlockerFactory {
+	return config.BlockerFactory{
+		DateLists:    conf.DateLists,
+		DomainGroups: conf.DomainGroups,
+		Schedules:    conf.Schedules,
+		Timezone:     conf.Timezone,
 	}
-	slog.Debug("Configuration loaded", "config", conf)
+}
 
-	f := config.BlockerFactory{}
+func genBlockers(conf *config.Config) ([]domain.Blocker, error) {
+	f := newBlockerFactory(conf)
 	blockers, err := f.GenBlockers(context.Background(), conf.Blockers)
 	if err != nil {
-		slog.Error("failed to create blockers from config", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
 	slog.Debug("Blockers created from config", "blockers", blockers)
+	return blockers, nil
+}
+
+// timezone returns the time zone of the configuration, the local time zone if unset.
+func timezone(conf *config.Config) *time.Location {
+	if conf.Timezone == "" {
+		return time.Local
+	}
+	// The time zone was validated when loading the configuration.
+	loc, _ := time.LoadLocation(conf.Timezone)
+	return loc
+}
+
+// loadBlockers loads the configuration file at path and creates its blockers.
+func loadBlockers(path string) (*config.Config, []domain.Blocker, error) {
+	conf, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockers, err := genBlockers(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conf, blockers, nil
+}
+
+// reloader replaces the blockers of the server with those of a reloaded configuration, one reload at a time.
+// If the configuration is invalid, the server keeps the current blockers and reports the error.
+// Server settings such as the port only take effect on restart.
+type reloader struct {
+	mu   sync.Mutex
+	path string
+	srv  *presentation.Server
+}
+
+func (r *reloader) reload() {
+	conf, err := config.LoadConfig(r.path)
+	r.apply(conf, err)
+}
+
+func (r *reloader) apply(conf *config.Config, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	srv = presentation.NewServer(blockers, presentation.ServerConfig{
-		Port: uint(conf.Server.Port),
+	var blockers []domain.Blocker
+	if err == nil {
+		blockers, err = genBlockers(conf)
+	}
+	if err != nil {
+		slog.Error("failed to reload config, keeping the current blockers", "error", err)
+		r.srv.SetReloadError(err)
+		return
+	}
+	r.srv.SetBlockers(blockers)
+	slog.Info("Configuration reloaded", "blockers", len(blockers))
+}
+
+// serve runs the server until SIGTERM or SIGINT, reloading the configuration when the file changes or on SIGHUP.
+func serve() int {
+	showVersion()
+	path := configPath()
+	conf, blockers, err := loadBlockers(path)
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		return exitConfigError
+	}
+	slog.Debug("Configuration loaded", "config", conf)
+
+	srv := presentation.NewServer(blockers, presentation.ServerConfig{
+		Port:    uint(conf.Server.Port),
+		RPZFile: conf.Server.RPZFile,
 	})
+	r := &reloader{path: path, srv: srv}
+	config.WatchConfig(path, r.apply)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	reloads := make(chan os.Signal, 1)
+	signal.Notify(reloads, syscall.SIGHUP)
+	defer signal.Stop(reloads)
+
+	l := &lifecycle{server: srv, reload: r.reload, reloads: reloads, shutdownTimeout: shutdownTimeout}
+	return l.run(ctx)
+}
+
+const usage = `Usage: sinkhole_detox [command] [flags]
+
+Commands:
+  serve      serve the blocked domains over HTTP (default)
+  validate   check configuration files and report every problem
+  simulate   print when each domain is blocked in a range of dates
+  render     write the blocked domains to stdout or a file, optionally at every schedule change
+
+The configuration file is read from $CONFIG_FILE_PATH, or config/config.yaml if unset.
+Run "sinkhole_detox <command> -h" for the flags of a command.
+`
+
+// run runs the command named by the first argument and returns the exit code. It serves if there is no argument.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return serve()
+	}
+	switch args[0] {
+	case "serve":
+		if len(args) > 1 {
+			fmt.Fprintf(stderr, "serve takes no arguments\n")
+			return exitUsage
+		}
+		return serve()
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "simulate":
+		return simulate(args[1:], stdout, stderr)
+	case "render":
+		return render(context.Background(), args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command: %s\n%s", args[0], usage)
+		return exitUsage
+	}
 }
 
 func main() {
-	srv.Start()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"help"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "Usage: sinkhole_detox")
+
+	stdout.Reset()
+	assert.Equal(t, exitUsage, run([]string{"deploy"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unknown command: deploy")
+
+	assert.Equal(t, exitUsage, run([]string{"serve", "now"}, &stdout, &stderr))
+
+	path := writeConfig(t, validConfig)
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"validate", path}, &stdout, &stderr))
+	assert.Equal(t, path+": ok\n", stdout.String())
+}
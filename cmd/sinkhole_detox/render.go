@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/alkshmir/sinkhole-detox/internal/infra/config"
+	"github.com/alkshmir/sinkhole-detox/internal/presentation"
+)
+
+// render writes the domains blocked now, or at -at, in an output format to stdout or a file,
+// for DNS servers that cannot pull the list over HTTP. With -watch, it keeps the file up to date
+// at every schedule change until ctx is done or SIGTERM or SIGINT is received.
+func render(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "domains", "output format: "+strings.Join(presentation.FormatNames(), ", "))
+	at := fs.String("at", "", "time to evaluate the schedule at, YYYY-MM-DD or RFC 3339 (default now)")
+	output := fs.String("o", "", "file to replace atomically with the output (default stdout)")
+	watch := fs.Bool("watch", false, "rewrite the file at every schedule change, requires -o")
+	hook := fs.String("hook", "", "shell command to run after the file is written, e.g. to reload dnsmasq")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sinkhole_detox render [flags] [config file]\n\n"+
+			"Writes the domains blocked now in an output format.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	switch {
+	case fs.NArg() > 1:
+		fmt.Fprintf(stderr, "render takes at most one config file\n")
+		return exitUsage
+	case !slices.Contains(presentation.FormatNames(), *format):
+		fmt.Fprintf(stderr, "unknown format: %s\n", *format)
+		return exitUsage
+	case *watch && *output == "":
+		fmt.Fprintf(stderr, "-watch requires -o\n")
+		return exitUsage
+	case *watch && *at != "":
+		fmt.Fprintf(stderr, "-watch cannot be combined with -at\n")
+		return exitUsage
+	case *hook != "" && *output == "":
+		fmt.Fprintf(stderr, "-hook requires -o\n")
+		return exitUsage
+	}
+	path := configPath()
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	if *output == "" {
+		// Informational logs would mix with the output.
+		previous := slog.SetLogLoggerLevel(slog.LevelWarn)
+		defer slog.SetLogLoggerLevel(previous)
+	}
+
+	conf, blockers, err := loadBlockers(path)
+	if err != nil {
+		printProblems(stderr, path, config.Problems(err))
+		return exitConfigError
+	}
+	loadedAt := time.Now()
+	generator := domain.NewHostsGenerator(blockers)
+
+	w := fileWriter{path: *output, hook: *hook, stdout: stdout, stderr: stderr}
+	if *watch {
+		ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+		defer stop()
+		slog.Info("watching the schedule", "path", *output, "format", *format)
+		// Failed writes are logged and retried, so watching only ends with ctx.
+		presentation.WatchRender(ctx, generator, *format, loadedAt, func(out string) error {
+			err := w.write(out)
+			if errors.Is(err, errHook) {
+				// The file is written, so the hook only runs again when the output changes.
+				slog.Error("hook failed", "hook", *hook, "error", err)
+				return nil
+			}
+			if err != nil {
+				slog.Error("failed to write output", "path", *output, "error", err)
+			}
+			return err
+		})
+		return exitOK
+	}
+
+	t := loadedAt
+	if *at != "" {
+		if t, err = parseTimeFlag(*at, timezone(conf)); err != nil {
+			fmt.Fprintf(stderr, "invalid -at: %v\n", err)
+			return exitUsage
+		}
+	}
+	out, err := presentation.Render(generator, *format, t, loadedAt)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitUsage
+	}
+	if err := w.write(out); err != nil {
+		fmt.Fprintf(stderr, "failed to write output: %v\n", err)
+		return exitOutputError
+	}
+	return exitOK
+}
+
+// errHook is wrapped by errors of the hook of a fileWriter.
+var errHook = errors.New("hook failed")
+
+// fileWriter writes rendered output to a file, or stdout if path is empty, and runs the hook after writing the file.
+type fileWriter struct {
+	path   string
+	hook   string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (w fileWriter) write(out string) error {
+	if w.path == "" {
+		_, err := io.WriteString(w.stdout, out)
+		return err
+	}
+	if err := presentation.WriteFileAtomic(w.path, []byte(out)); err != nil {
+		return err
+	}
+	slog.Info("output written", "path", w.path)
+	if w.hook == "" {
+		return nil
+	}
+	// The hook is not canceled on shutdown, so that a DNS server is not left half reloaded.
+	cmd := exec.Command("sh", "-c", w.hook)
+	cmd.Stdout, cmd.Stderr = w.stdout, w.stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %w", errHook, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	config := writeConfig(t, simulateConfig)
+
+	var stdout, stderr bytes.Buffer
+	code := render(context.Background(), []string{"-format", "hosts", "-at", "2025-01-06T10:00:00+09:00", config}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, "stderr: %s", &stderr)
+	assert.Equal(t, "0.0.0.0 twitter.com\n", stdout.String())
+
+	stdout.Reset()
+	code = render(context.Background(), []string{"-at", "2025-01-06T12:30:00+09:00", config}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, "stderr: %s", &stderr)
+	assert.Equal(t, "", stdout.String(), "expected nothing to be blocked during the allow rule")
+}
+
+func TestRender_File(t *testing.T) {
+	t.Parallel()
+
+	config := writeConfig(t, simulateConfig)
+	dir := t.TempDir()
+	output := filepath.Join(dir, "blocked.conf")
+	marker := filepath.Join(dir, "reloaded")
+
+	var stdout, stderr bytes.Buffer
+	code := render(context.Background(), []string{
+		"-format", "dnsmasq", "-at", "2025-01-06T10:00:00+09:00", "-o", output, "-hook", "cat " + output + " > " + marker, config,
+	}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, "stderr: %s", &stderr)
+
+	data, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, "host-record=twitter.com,0.0.0.0\n", string(data))
+	hooked, err := os.ReadFile(marker)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(hooked), "expected the hook to run after the file was written")
+
+	code = render(context.Background(), []string{"-o", output, "-hook", "exit 1", config}, &stdout, &stderr)
+	assert.Equal(t, exitOutputError, code, "expected a failed hook to be reported")
+
+	code = render(context.Background(), []string{"-o", filepath.Join(dir, "missing", "blocked.conf"), config}, &stdout, &stderr)
+	assert.Equal(t, exitOutputError, code)
+}
+
+func TestRender_Watch(t *testing.T) {
+	t.Parallel()
+
+	config := writeConfig(t, simulateConfig)
+	dir := t.TempDir()
+	output := filepath.Join(dir, "blocked.rpz")
+	marker := filepath.Join(dir, "reloaded")
+
+	// Watching ends with the context, after the file was written once.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var stdout, stderr bytes.Buffer
+	code := render(ctx, []string{"-format", "rpz", "-watch", "-o", output, "-hook", "touch " + marker, config}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, "stderr: %s", &stderr)
+
+	data, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "IN SOA")
+	assert.FileExists(t, marker)
+}
+
+func TestRender_Usage(t *testing.T) {
+	t.Parallel()
+
+	config := writeConfig(t, simulateConfig)
+	output := filepath.Join(t.TempDir(), "blocked.conf")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "should reject unknown formats", args: []string{"-format", "bind", config}},
+		{name: "should reject watching stdout", args: []string{"-watch", config}},
+		{name: "should reject watching at a fixed time", args: []string{"-watch", "-o", output, "-at", "2025-01-06", config}},
+		{name: "should reject hooks without a file", args: []string{"-hook", "true", config}},
+		{name: "should reject invalid times", args: []string{"-at", "noon", config}},
+		{name: "should reject several config files", args: []string{config, config}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, exitUsage, render(context.Background(), tt.args, &stdout, &stderr))
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
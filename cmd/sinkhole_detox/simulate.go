@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/alkshmir/sinkhole-detox/internal/infra/config"
+)
+
+// defaultSimulateDays is the number of days simulated if -to is not set.
+const defaultSimulateDays = 7
+
+type simulation struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Domains []domainSimulation `json:"domains"`
+}
+
+type domainSimulation struct {
+	Domain  string             `json:"domain"`
+	Windows []windowSimulation `json:"windows"`
+}
+
+type windowSimulation struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// simulate prints the intervals in which each domain of a configuration is blocked in a range of time,
+// so that changes of a schedule can be reviewed before they are deployed.
+func simulate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "", "start of the range, YYYY-MM-DD or RFC 3339 (default today)")
+	to := fs.String("to", "", fmt.Sprintf("end of the range, exclusive, YYYY-MM-DD or RFC 3339 (default %d days after -from)", defaultSimulateDays))
+	output := fs.String("output", "text", "output format, text or json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sinkhole_detox simulate [flags] [config file]\n\n"+
+			"Prints the intervals in which each domain is blocked. Dates and times without offset are in the\n"+
+			"timezone of the configuration, which is also used for the output.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "simulate takes at most one config file\n")
+		return exitUsage
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "unknown output format: %s\n", *output)
+		return exitUsage
+	}
+	path := configPath()
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	previous := slog.SetLogLoggerLevel(slog.LevelWarn)
+	defer slog.SetLogLoggerLevel(previous)
+
+	conf, blockers, err := loadBlockers(path)
+	if err != nil {
+		printProblems(stderr, path, config.Problems(err))
+		return exitConfigError
+	}
+	loc := timezone(conf)
+
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if *from != "" {
+		if start, err = parseTimeFlag(*from, loc); err != nil {
+			fmt.Fprintf(stderr, "invalid -from: %v\n", err)
+			return exitUsage
+		}
+	}
+	end := start.AddDate(0, 0, defaultSimulateDays)
+	if *to != "" {
+		if end, err = parseTimeFlag(*to, loc); err != nil {
+			fmt.Fprintf(stderr, "invalid -to: %v\n", err)
+			return exitUsage
+		}
+	}
+	if !start.Before(end) {
+		fmt.Fprintf(stderr, "-from must be before -to\n")
+		return exitUsage
+	}
+
+	s := newSimulation(domain.NewHostsGenerator(blockers).Schedule(start, end), start, end, loc)
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(s)
+		return exitOK
+	}
+	s.writeTable(stdout)
+	return exitOK
+}
+
+func newSimulation(schedule []domain.DomainWindows, from, to time.Time, loc *time.Location) simulation {
+	s := simulation{From: from.In(loc), To: to.In(loc), Domains: []domainSimulation{}}
+	for _, dw := range schedule {
+		d := domainSimulation{Domain: dw.Domain, Windows: []windowSimulation{}}
+		for _, w := range dw.Windows {
+			d.Windows = append(d.Windows, windowSimulation{Start: w.Start.In(loc), End: w.End.In(loc)})
+		}
+		s.Domains = append(s.Domains, d)
+	}
+	return s
+}
+
+// writeTable writes a row for every window, and a row for each domain that is never blocked.
+// Windows are cut at the start and end of the simulated range.
+func (s simulation) writeTable(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DOMAIN\tSTART\tEND\tDURATION")
+	for _, d := range s.Domains {
+		if len(d.Windows) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t0s\n", d.Domain)
+			continue
+		}
+		for _, win := range d.Windows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Domain, formatTableTime(win.Start), formatTableTime(win.End), win.End.Sub(win.Start))
+		}
+	}
+	tw.Flush()
+}
+
+// formatTableTime formats t with its weekday, showing seconds only if it has any.
+func formatTableTime(t time.Time) string {
+	if t.Second() != 0 {
+		return t.Format("Mon 2006-01-02 15:04:05")
+	}
+	return t.Format("Mon 2006-01-02 15:04")
+}
+
+// parseTimeFlag parses a date, meaning its start in loc, or an RFC 3339 time.
+func parseTimeFlag(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither YYYY-MM-DD nor RFC 3339", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const simulateConfig = `
+timezone: Asia/Tokyo
+blockers:
+  - name: twitter
+    domain: twitter.com
+    rules:
+      - type: weekday
+        ops: block
+        start: "09:00"
+        end: "18:00"
+        weekdays: [1, 2, 3, 4, 5]
+      - type: everyday
+        ops: allow
+        start: "12:00"
+        end: "13:00"
+  - name: news
+    domain: news.example.com
+    rules:
+      - type: everyday
+        ops: block
+        start: "22:00"
+        end: "06:00"
+  - name: games
+    domain: games.example.com
+    rules:
+      - type: daterange
+        ops: block
+        start_at: "2026-01-01"
+        end_at: "2026-02-01"
+`
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, simulateConfig)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "should print the windows of each domain in the timezone of the configuration",
+			args: []string{"-from", "2025-01-05", "-to", "2025-01-07", path},
+			expected: "DOMAIN             START                 END                   DURATION\n" +
+				"twitter.com        Mon 2025-01-06 09:00  Mon 2025-01-06 12:00  3h0m0s\n" +
+				"twitter.com        Mon 2025-01-06 13:00  Mon 2025-01-06 18:00  5h0m0s\n" +
+				"news.example.com   Sun 2025-01-05 00:00  Sun 2025-01-05 06:00  6h0m0s\n" +
+				"news.example.com   Sun 2025-01-05 22:00  Mon 2025-01-06 06:00  8h0m0s\n" +
+				"news.example.com   Mon 2025-01-06 22:00  Tue 2025-01-07 00:00  2h0m0s\n" +
+				"games.example.com  -                     -                     0s\n",
+		},
+		{
+			name: "should print json",
+			args: []string{"-from", "2025-01-06T08:00:00+09:00", "-to", "2025-01-06T10:00:00+09:00", "-output", "json", path},
+			expected: `{
+  "from": "2025-01-06T08:00:00+09:00",
+  "to": "2025-01-06T10:00:00+09:00",
+  "domains": [
+    {
+      "domain": "twitter.com",
+      "windows": [
+        {
+          "start": "2025-01-06T09:00:00+09:00",
+          "end": "2025-01-06T10:00:00+09:00"
+        }
+      ]
+    },
+    {
+      "domain": "news.example.com",
+      "windows": []
+    },
+    {
+      "domain": "games.example.com",
+      "windows": []
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, exitOK, simulate(tt.args, &stdout, &stderr), "stderr: %s", &stderr)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
+
+func TestSimulate_Errors(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, simulateConfig)
+	invalid := writeConfig(t, "blockers:\n  - name: x\n    rules:\n      - type: weekly\n")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "should reject invalid dates", args: []string{"-from", "tomorrow", path}, expected: exitUsage},
+		{name: "should reject empty ranges", args: []string{"-from", "2025-01-06", "-to", "2025-01-06", path}, expected: exitUsage},
+		{name: "should reject unknown output formats", args: []string{"-output", "csv", path}, expected: exitUsage},
+		{name: "should reject several config files", args: []string{path, path}, expected: exitUsage},
+		{name: "should report invalid configurations", args: []string{invalid}, expected: exitConfigError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, tt.expected, simulate(tt.args, &stdout, &stderr))
+			assert.Empty(t, stdout.String())
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	d, err := parseTimeFlag("2025-01-06", tokyo)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, tokyo), d)
+
+	ts, err := parseTimeFlag("2025-01-06T09:00:00Z", tokyo)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC).Equal(ts))
+
+	_, err = parseTimeFlag("06/01/2025", tokyo)
+	assert.Error(t, err)
+}
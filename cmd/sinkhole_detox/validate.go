@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/alkshmir/sinkhole-detox/internal/infra/config"
+)
+
+// validate checks the configuration files given as arguments, or the configured one, and prints every problem.
+// It returns exitConfigError if any file has a problem.
+func validate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sinkhole_detox validate [config files]\n\n"+
+			"Checks the configuration files, $CONFIG_FILE_PATH or config/config.yaml by default,\n"+
+			"and prints every problem with the blocker name and rule index.\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{configPath()}
+	}
+
+	// Problems are the output; informational logs of loading the files would only hide them.
+	previous := slog.SetLogLoggerLevel(slog.LevelWarn)
+	defer slog.SetLogLoggerLevel(previous)
+
+	code := exitOK
+	for _, path := range paths {
+		problems := validateConfig(path)
+		if len(problems) == 0 {
+			fmt.Fprintf(stdout, "%s: ok\n", path)
+			continue
+		}
+		code = exitConfigError
+		printProblems(stdout, path, problems)
+	}
+	return code
+}
+
+// validateConfig returns every problem of the configuration file at path.
+// It does not use loadBlockers, which stops at the time zones LoadConfig checks; GenBlockers checks them as well.
+func validateConfig(path string) []error {
+	conf, err := config.ReadConfig(path)
+	if err != nil {
+		return config.Problems(err)
+	}
+	_, err = genBlockers(conf)
+	return config.Problems(err)
+}
+
+// printProblems prints every problem of a configuration file on its own line.
+func printProblems(w io.Writer, path string, problems []error) {
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s: %v\n", path, p)
+	}
+}
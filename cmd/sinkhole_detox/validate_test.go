@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfig writes content to a config file in a temporary directory and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+const validConfig = `
+blockers:
+  - name: twitter
+    domain: twitter.com
+    rules:
+      - type: weekday
+        ops: block
+        start: "09:00"
+        end: "18:00"
+        weekdays: [1, 2, 3, 4, 5]
+`
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := writeConfig(t, validConfig)
+	invalid := writeConfig(t, `
+blockers:
+  - name: twitter
+    domain: twitter.com
+    rules:
+      - type: weekday
+        ops: block
+        start: "09:00"
+        end: "18:00"
+        weekdays: [1, 2, 3, 4, 5]
+      - type: everyday
+        ops: deny
+        start: "12:00"
+        end: "13:00"
+  - name: news
+    domain: news.example.com
+    schedules: [missing]
+`)
+
+	spread := writeConfig(t, `
+timezone: Mars/Olympus
+date_lists:
+  holidays:
+    dates: ["2026-13-01"]
+blockers:
+  - name: a
+    domain: a.example.com
+    timezone: Mars/Olympus
+  - name: b
+    domain: b.example.com
+    domain_groups: [nope1, nope2]
+    skip_dates: [x, y]
+    schedules: [missing]
+    rules:
+      - type: everyday
+        ops: block
+        start: "25:00"
+        end: "18:00"
+      - type: everyday
+        ops: deny
+        start: "12:00"
+        end: "13:00"
+`)
+
+	badAddresses := writeConfig(t, `
+blockers:
+  - name: twitter
+    domain: twitter.com
+    forward_to: "0.0.0.O"
+    forward_to_v6: "0.0.0.0"
+`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+		output   []string
+	}{
+		{
+			name:     "should report valid files",
+			args:     []string{valid},
+			expected: exitOK,
+			output:   []string{valid + ": ok\n"},
+		},
+		{
+			name:     "should report every problem with the blocker and rule",
+			args:     []string{invalid},
+			expected: exitConfigError,
+			output: []string{
+				invalid + ": invalid blocker twitter: rule 1: failed to create everyday rule: ",
+				invalid + ": invalid blocker news: unknown schedule: missing\n",
+			},
+		},
+		{
+			name:     "should report problems of time zones, definitions and blockers together",
+			args:     []string{spread},
+			expected: exitConfigError,
+			output: []string{
+				spread + ": invalid date list holidays: failed to parse date 2026-13-01",
+				spread + ": unknown timezone Mars/Olympus",
+				spread + ": invalid blocker a: unknown timezone Mars/Olympus",
+				spread + ": invalid blocker b: unknown domain group: nope1\n",
+				spread + ": invalid blocker b: unknown domain group: nope2\n",
+				spread + ": invalid blocker b: unknown date list: x\n",
+				spread + ": invalid blocker b: unknown date list: y\n",
+				spread + ": invalid blocker b: unknown schedule: missing\n",
+				spread + ": invalid blocker b: rule 0: failed to parse start time 25:00",
+				spread + ": invalid blocker b: rule 1: failed to create everyday rule",
+			},
+		},
+		{
+			name:     "should report invalid forward addresses instead of using the default",
+			args:     []string{badAddresses},
+			expected: exitConfigError,
+			output: []string{
+				badAddresses + ": invalid blocker twitter: forward_to must be an IP address: 0.0.0.O\n",
+				badAddresses + ": invalid blocker twitter: forward_to_v6 must be an IPv6 address: 0.0.0.0\n",
+			},
+		},
+		{
+			name:     "should check every file",
+			args:     []string{valid, invalid},
+			expected: exitConfigError,
+			output:   []string{valid + ": ok\n", invalid + ": invalid blocker news"},
+		},
+		{
+			name:     "should report files that cannot be read",
+			args:     []string{filepath.Join(t.TempDir(), "missing.yaml")},
+			expected: exitConfigError,
+			output:   []string{"failed to read config file"},
+		},
+		{
+			name:     "should reject unknown flags",
+			args:     []string{"-strict", valid},
+			expected: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, tt.expected, validate(tt.args, &stdout, &stderr), "stdout: %s, stderr: %s", &stdout, &stderr)
+			for _, o := range tt.output {
+				assert.Contains(t, stdout.String(), o)
+			}
+		})
+	}
+}
@@ -9,48 +9,169 @@ import (
 
 type Blocker struct {
 	Domain string // RFC 1035
+	// IncludeSubdomains extends blocking to all subdomains of Domain
+	// in output formats that support wildcards.
+	IncludeSubdomains bool
+	// Subdomains are known subdomains of Domain, e.g. "mobile.twitter.com", which are blocked along with it.
+	// Output formats without wildcard support list them to approximate IncludeSubdomains.
+	Subdomains []string
 	// ForwardTo is IP address to forward requests to if the domain is blocked.
 	// Usually this is 0.0.0.0
 	ForwardTo net.IP
+	// ForwardToV6 is an optional IPv6 address to forward AAAA requests to if the domain is blocked.
+	// Usually this is ::
+	ForwardToV6 net.IP
 	// Rules is a list of blocking Rules. Latter Rules take precedence over earlier ones.
 	Rules []BlockRule
+	// Location is the time zone in which Rules are evaluated.
+	// If nil, the location of the given time is used.
+	Location *time.Location
 }
 
 func (b *Blocker) IsBlocked(t time.Time) bool {
-	slog.Info("evaluating blocker for domain", "domain", b.Domain, "time", t)
-	blocked := false
+	return b.Explain(t).Blocked
+}
+
+// Explanation is the state of a blocker at a point in time and the rules that decided it.
+type Explanation struct {
+	Blocked bool
+	// Rules are the active rules that decided the state: those after the last active rule with the opposite operation.
+	// It is empty if no rule is active.
+	Rules []RuleMatch
+}
+
+// RuleMatch identifies an active rule of a blocker.
+type RuleMatch struct {
+	Index int // index in Blocker.Rules
+	Type  string
+	Op    BlockOps
+}
+
+// Explain evaluates the rules at t like IsBlocked and reports which of them decided the state.
+// It logs at debug level only, because searches such as NextChange call it many times per request.
+func (b *Blocker) Explain(t time.Time) Explanation {
+	if b.Location != nil {
+		t = t.In(b.Location)
+	}
+	slog.Debug("evaluating blocker for domain", "domain", b.Domain, "time", t)
+	var e Explanation
+	for i, rule := range b.Rules {
+		if !rule.IsActive(t) {
+			continue
+		}
+		blocked := rule.Ops() == BlockOpsBlock
+		if blocked != e.Blocked {
+			// The rule overrides the earlier ones.
+			e.Rules = nil
+		}
+		e.Blocked = blocked
+		e.Rules = append(e.Rules, RuleMatch{Index: i, Type: RuleType(rule), Op: rule.Ops()})
+	}
+	slog.Debug("blocker evaluation result", "domain", b.Domain, "blocked", e.Blocked)
+	return e
+}
+
+// RuleType returns the name of the kind of rule, matching the rule types of the configuration where possible.
+func RuleType(rule BlockRule) string {
+	switch r := rule.(type) {
+	case EveryDayRule:
+		return "everyday"
+	case WeekdayRule:
+		return "weekday"
+	case DateRangeRule:
+		return "daterange"
+	case RecurrenceRule:
+		switch r.Recurrence.(type) {
+		case CronSchedule:
+			return "cron"
+		case RRule:
+			return "rrule"
+		}
+		return "recurrence"
+	case EventRule:
+		return "event"
+	}
+	return "unknown"
+}
+
+// Windows returns the intervals in [from, to) in which the domain is blocked,
+// sorted and with adjacent intervals merged.
+func (b *Blocker) Windows(from, to time.Time) []Window {
+	if b.Location != nil {
+		from, to = from.In(b.Location), to.In(b.Location)
+	}
+	var blocked []Window
 	for _, rule := range b.Rules {
-		if rule.IsActive(t) {
-			switch rule.Ops() {
-			case BlockOpsBlock:
-				blocked = true
-				// case BlockOpsAllow:
-				// 	blocked = false
-			}
+		switch rule.Ops() {
+		case BlockOpsBlock:
+			blocked = mergeWindows(append(blocked, rule.Windows(from, to)...))
+		case BlockOpsAllow:
+			blocked = subtractWindows(blocked, rule.Windows(from, to))
 		}
 	}
-	slog.Info("blocker evaluation result", "domain", b.Domain, "blocked", blocked)
 	return blocked
 }
 
+// NextChange returns the earliest time in (t, t + horizon) at which the blocker starts or stops blocking,
+// or the zero time if there is none.
+//
+// The state can only change when a rule becomes active or inactive, so the changes of the rules are visited
+// in order until one of them changes the state; changes overridden by other rules are skipped.
+func (b *Blocker) NextChange(t time.Time, horizon time.Duration) time.Time {
+	if b.Location != nil {
+		t = t.In(b.Location)
+	}
+	end := t.Add(horizon)
+	blocked := b.IsBlocked(t)
+
+	next := make([]time.Time, len(b.Rules))
+	for i, rule := range b.Rules {
+		next[i] = rule.NextChange(t)
+	}
+	for {
+		var c time.Time
+		for _, n := range next {
+			if !n.IsZero() && (c.IsZero() || n.Before(c)) {
+				c = n
+			}
+		}
+		if c.IsZero() || !c.Before(end) {
+			return time.Time{}
+		}
+		if b.IsBlocked(c) != blocked {
+			return c
+		}
+		for i, n := range next {
+			if n.Equal(c) {
+				next[i] = b.Rules[i].NextChange(c)
+			}
+		}
+	}
+}
+
 type BlockRule interface {
 	Ops() BlockOps
 	IsActive(time.Time) bool
+	// NextChange returns the earliest time after t at which IsActive changes,
+	// or the zero time if it does not change within the range the rule searches, at least a year.
+	NextChange(t time.Time) time.Time
+	// Windows returns the intervals in which the rule is active, restricted to [from, to).
+	Windows(from, to time.Time) []Window
 }
 
 type BlockOps string
 
 const (
 	BlockOpsBlock BlockOps = "block"
-	// BlockOpsAllow BlockOps = "allow"
+	BlockOpsAllow BlockOps = "allow"
 )
 
 func (o BlockOps) Validate() error {
 	switch o {
 	case BlockOpsBlock:
 		return nil
-	// case BlockOpsAllow:
-	// 	return nil
+	case BlockOpsAllow:
+		return nil
 	default:
 		return fmt.Errorf("unknown block operation: %s", o)
 	}
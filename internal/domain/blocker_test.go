@@ -9,18 +9,33 @@ import (
 
 type MockRule struct {
 	Active bool
+	Op     BlockOps // defaults to BlockOpsBlock
 }
 
 var _ BlockRule = &MockRule{}
 
 func (m *MockRule) Ops() BlockOps {
-	return BlockOpsBlock
+	if m.Op == "" {
+		return BlockOpsBlock
+	}
+	return m.Op
 }
 
 func (m *MockRule) IsActive(t time.Time) bool {
 	return m.Active
 }
 
+func (m *MockRule) NextChange(t time.Time) time.Time {
+	return time.Time{}
+}
+
+func (m *MockRule) Windows(from, to time.Time) []Window {
+	if !m.Active {
+		return nil
+	}
+	return []Window{{Start: from, End: to}}
+}
+
 func TestBlocker_IsBlocked(t *testing.T) {
 	t.Parallel()
 
@@ -54,6 +69,42 @@ func TestBlocker_IsBlocked(t *testing.T) {
 			time:     time.Now(),
 			expected: false,
 		},
+		{
+			name: "should return false if a later allow rule is active",
+			blocker: Blocker{
+				Domain: "example.com",
+				Rules: []BlockRule{
+					&MockRule{Active: true},
+					&MockRule{Active: true, Op: BlockOpsAllow},
+				},
+			},
+			time:     time.Now(),
+			expected: false,
+		},
+		{
+			name: "should return true if a later block rule overrides an earlier allow rule",
+			blocker: Blocker{
+				Domain: "example.com",
+				Rules: []BlockRule{
+					&MockRule{Active: true, Op: BlockOpsAllow},
+					&MockRule{Active: true},
+				},
+			},
+			time:     time.Now(),
+			expected: true,
+		},
+		{
+			name: "should ignore inactive allow rules",
+			blocker: Blocker{
+				Domain: "example.com",
+				Rules: []BlockRule{
+					&MockRule{Active: true},
+					&MockRule{Active: false, Op: BlockOpsAllow},
+				},
+			},
+			time:     time.Now(),
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -74,6 +125,10 @@ func TestBlockOps_Validate(t *testing.T) {
 			name: "should return no error for valid ops",
 			ops:  BlockOpsBlock,
 		},
+		{
+			name: "should return no error for allow ops",
+			ops:  BlockOpsAllow,
+		},
 		{
 			name:    "should return error for invalid ops",
 			ops:     "invalid",
@@ -91,3 +146,313 @@ func TestBlockOps_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBlocker_IsBlocked_Precedence(t *testing.T) {
+	t.Parallel()
+
+	workHours := WeekdayRule{
+		Op:       BlockOpsBlock,
+		From:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+	lunch := EveryDayRule{
+		Op:   BlockOpsAllow,
+		From: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+		To:   time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+	}
+	lunchMeeting := WeekdayRule{
+		Op:       BlockOpsBlock,
+		From:     time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+		To:       time.Date(0, 1, 1, 12, 30, 0, 0, time.UTC),
+		Weekdays: []time.Weekday{time.Wednesday},
+	}
+	fridayAfternoon := WeekdayRule{
+		Op:       BlockOpsAllow,
+		From:     time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC),
+		To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		Weekdays: []time.Weekday{time.Friday},
+	}
+
+	tests := []struct {
+		name     string
+		rules    []BlockRule
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should block during work hours",
+			rules:    []BlockRule{workHours, lunch},
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			expected: true,
+		},
+		{
+			name:     "should allow lunch break inside work hours",
+			rules:    []BlockRule{workHours, lunch},
+			time:     time.Date(2025, 1, 6, 12, 30, 0, 0, time.UTC), // Monday
+			expected: false,
+		},
+		{
+			name:     "should block again after lunch break",
+			rules:    []BlockRule{workHours, lunch},
+			time:     time.Date(2025, 1, 6, 14, 0, 0, 0, time.UTC), // Monday
+			expected: true,
+		},
+		{
+			name:     "should not block on weekends",
+			rules:    []BlockRule{workHours, lunch},
+			time:     time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC), // Saturday
+			expected: false,
+		},
+		{
+			name:     "should not let an earlier allow rule override a later block rule",
+			rules:    []BlockRule{lunch, workHours},
+			time:     time.Date(2025, 1, 6, 12, 30, 0, 0, time.UTC), // Monday
+			expected: true,
+		},
+		{
+			name:     "should apply the last active rule when three rules overlap",
+			rules:    []BlockRule{workHours, lunch, lunchMeeting},
+			time:     time.Date(2025, 1, 8, 12, 15, 0, 0, time.UTC), // Wednesday
+			expected: true,
+		},
+		{
+			name:     "should keep the allow rule when the later block rule is inactive",
+			rules:    []BlockRule{workHours, lunch, lunchMeeting},
+			time:     time.Date(2025, 1, 8, 12, 45, 0, 0, time.UTC), // Wednesday
+			expected: false,
+		},
+		{
+			name:     "should allow weekday specific exception",
+			rules:    []BlockRule{workHours, fridayAfternoon},
+			time:     time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC), // Friday
+			expected: false,
+		},
+		{
+			name:     "should not apply weekday specific exception on other days",
+			rules:    []BlockRule{workHours, fridayAfternoon},
+			time:     time.Date(2025, 1, 9, 16, 0, 0, 0, time.UTC), // Thursday
+			expected: true,
+		},
+		{
+			name:     "should not block with only allow rules",
+			rules:    []BlockRule{lunch, fridayAfternoon},
+			time:     time.Date(2025, 1, 10, 12, 30, 0, 0, time.UTC), // Friday
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Blocker{Domain: "example.com", Rules: tt.rules}
+			assert.Equal(t, tt.expected, b.IsBlocked(tt.time))
+		})
+	}
+}
+
+func TestBlocker_Windows(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC) // Monday
+	at := func(hour, min int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+	workHours := WeekdayRule{
+		Op:       BlockOpsBlock,
+		From:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+	lunch := EveryDayRule{
+		Op:   BlockOpsAllow,
+		From: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+		To:   time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+	}
+	evening := EveryDayRule{
+		Op:   BlockOpsBlock,
+		From: time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		To:   time.Date(0, 1, 1, 20, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		rules    []BlockRule
+		expected []Window
+	}{
+		{
+			name:  "should cut allowed windows out of blocked windows",
+			rules: []BlockRule{workHours, lunch},
+			expected: []Window{
+				{Start: at(9, 0), End: at(12, 0)},
+				{Start: at(13, 0), End: at(18, 0)},
+			},
+		},
+		{
+			name:  "should merge adjacent blocked windows",
+			rules: []BlockRule{workHours, evening},
+			expected: []Window{
+				{Start: at(9, 0), End: at(20, 0)},
+			},
+		},
+		{
+			name:  "should not cut earlier allowed windows out of later blocked windows",
+			rules: []BlockRule{lunch, workHours},
+			expected: []Window{
+				{Start: at(9, 0), End: at(18, 0)},
+			},
+		},
+		{
+			name:     "should return no windows without block rules",
+			rules:    []BlockRule{lunch},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Blocker{Domain: "example.com", Rules: tt.rules}
+			assert.Equal(t, tt.expected, b.Windows(day, day.AddDate(0, 0, 1)))
+		})
+	}
+}
+
+func TestBlocker_Location(t *testing.T) {
+	t.Parallel()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	workHours := EveryDayRule{
+		Op:   BlockOpsBlock,
+		From: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		To:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+	}
+	inJST := Blocker{Domain: "example.com", Rules: []BlockRule{workHours}, Location: jst}
+	inTimeLocation := Blocker{Domain: "example.com", Rules: []BlockRule{workHours}}
+
+	at := time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC) // 10:00 JST
+	assert.True(t, inJST.IsBlocked(at), "expected rules to be evaluated in the blocker location")
+	assert.False(t, inTimeLocation.IsBlocked(at), "expected rules to be evaluated in the location of the time")
+
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, jst)
+	assert.Equal(t, []Window{
+		{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, jst), End: time.Date(2025, 1, 6, 18, 0, 0, 0, jst)},
+	}, inJST.Windows(day.UTC(), day.AddDate(0, 0, 1).UTC()))
+}
+
+func TestBlocker_Explain(t *testing.T) {
+	t.Parallel()
+
+	active := func(op BlockOps) BlockRule {
+		return &MockRule{Active: true, Op: op}
+	}
+	inactive := &MockRule{}
+	match := func(index int, op BlockOps) RuleMatch {
+		return RuleMatch{Index: index, Type: "unknown", Op: op}
+	}
+
+	tests := []struct {
+		name     string
+		rules    []BlockRule
+		expected Explanation
+	}{
+		{
+			name:     "should not be blocked without active rules",
+			rules:    []BlockRule{inactive},
+			expected: Explanation{},
+		},
+		{
+			name:     "should report the active block rules",
+			rules:    []BlockRule{active(BlockOpsBlock), inactive, active(BlockOpsBlock)},
+			expected: Explanation{Blocked: true, Rules: []RuleMatch{match(0, BlockOpsBlock), match(2, BlockOpsBlock)}},
+		},
+		{
+			name:     "should report the allow rule overriding block rules",
+			rules:    []BlockRule{active(BlockOpsBlock), active(BlockOpsAllow)},
+			expected: Explanation{Blocked: false, Rules: []RuleMatch{match(1, BlockOpsAllow)}},
+		},
+		{
+			name:     "should report only the rules after the last override",
+			rules:    []BlockRule{active(BlockOpsBlock), active(BlockOpsAllow), active(BlockOpsBlock), active(BlockOpsBlock)},
+			expected: Explanation{Blocked: true, Rules: []RuleMatch{match(2, BlockOpsBlock), match(3, BlockOpsBlock)}},
+		},
+		{
+			name:     "should report allow rules without active block rules",
+			rules:    []BlockRule{inactive, active(BlockOpsAllow)},
+			expected: Explanation{Blocked: false, Rules: []RuleMatch{match(1, BlockOpsAllow)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Blocker{Domain: "example.com", Rules: tt.rules}
+			e := b.Explain(time.Now())
+			assert.Equal(t, tt.expected, e)
+			assert.Equal(t, e.Blocked, b.IsBlocked(time.Now()))
+		})
+	}
+}
+
+func TestRuleType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rule     BlockRule
+		expected string
+	}{
+		{rule: EveryDayRule{}, expected: "everyday"},
+		{rule: WeekdayRule{}, expected: "weekday"},
+		{rule: DateRangeRule{}, expected: "daterange"},
+		{rule: RecurrenceRule{Recurrence: CronSchedule{}}, expected: "cron"},
+		{rule: RecurrenceRule{Recurrence: RRule{}}, expected: "rrule"},
+		{rule: EventRule{}, expected: "event"},
+		{rule: &MockRule{}, expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RuleType(tt.rule))
+		})
+	}
+}
+
+func TestBlocker_NextChange(t *testing.T) {
+	t.Parallel()
+
+	b := exampleBlocker()[0] // twitter.com
+	monday := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+
+	assert.Equal(t, at(5, 0), b.NextChange(at(1, 0), 24*time.Hour), "expected the end of the current window")
+	assert.Equal(t, at(8, 0), b.NextChange(at(5, 0), 24*time.Hour), "expected the start of the next window")
+	assert.True(t, b.NextChange(at(5, 0), time.Hour).IsZero(), "expected no change within the horizon")
+
+	overridden := Blocker{Domain: "example.com", Rules: []BlockRule{
+		EveryDayRule{Op: BlockOpsBlock, From: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), To: time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)},
+		EveryDayRule{Op: BlockOpsBlock, From: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC), To: time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC)},
+	}}
+	assert.Equal(t, at(18, 0), overridden.NextChange(at(10, 0), 24*time.Hour), "expected changes of overridden rules to be skipped")
+}
+
+func TestBlocker_NextChange_MatchesWindows(t *testing.T) {
+	t.Parallel()
+
+	b := exampleBlocker()[0]
+	b.Location = mustLoadLocation(t, "America/New_York")
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for tm := start; tm.Before(start.AddDate(0, 0, 14)); tm = tm.Add(45 * time.Minute) {
+		var expected time.Time
+		end := tm.Add(48 * time.Hour)
+		for _, w := range b.Windows(tm, end) {
+			if w.Start.After(tm) {
+				expected = w.Start
+				break
+			}
+			if w.End.Before(end) {
+				expected = w.End
+				break
+			}
+		}
+		assert.True(t, expected.Equal(b.NextChange(tm, 48*time.Hour)), "unexpected next change from %v", tm)
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// wallTime returns the instant at which the wall clock in loc shows the given date and time.
+//
+// Wall clock times are not always unique across daylight saving time transitions:
+//   - a time skipped by a forward transition resolves to the transition itself,
+//     i.e. the first instant after the gap.
+//   - a time repeated by a backward transition resolves to its first occurrence.
+func wallTime(year int, month time.Month, day, hour, min, sec int, loc *time.Location) time.Time {
+	naive := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	// Zone transitions are assumed to be at least a day apart,
+	// so the offsets a day before and after are the only candidates.
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	var found time.Time
+	for _, offset := range []int{before, after} {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if !sameWallClock(t, naive) {
+			continue
+		}
+		if found.IsZero() || t.Before(found) {
+			found = t
+		}
+	}
+	if !found.IsZero() {
+		return found
+	}
+
+	// The wall clock time falls into a gap. Using the offset before the transition
+	// yields an instant after it, whose zone starts at the transition.
+	start, _ := naive.Add(-time.Duration(before) * time.Second).In(loc).ZoneBounds()
+	return start
+}
+
+func sameWallClock(t, naive time.Time) bool {
+	y, m, d := t.Date()
+	ny, nm, nd := naive.Date()
+	return y == ny && m == nm && d == nd &&
+		t.Hour() == naive.Hour() && t.Minute() == naive.Minute() && t.Second() == naive.Second()
+}
+
+// civilDate returns midnight UTC of the calendar date of t in its own location.
+// It is used to do calendar arithmetic without being affected by time zone transitions.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
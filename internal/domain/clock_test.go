@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Load timezone data
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestWallTime(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		date     [3]int
+		clock    [3]int
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "should resolve a regular time",
+			date:     [3]int{2026, 1, 5},
+			clock:    [3]int{9, 30, 15},
+			loc:      ny,
+			expected: time.Date(2026, 1, 5, 14, 30, 15, 0, time.UTC),
+		},
+		{
+			name:     "should resolve a time in UTC",
+			date:     [3]int{2026, 3, 8},
+			clock:    [3]int{2, 30, 0},
+			loc:      time.UTC,
+			expected: time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "should resolve a time skipped by a forward transition to the transition",
+			date:     [3]int{2026, 3, 8},
+			clock:    [3]int{2, 30, 0},
+			loc:      ny,
+			expected: time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), // 03:00 EDT
+		},
+		{
+			name:     "should resolve the start of a gap to the transition",
+			date:     [3]int{2026, 3, 8},
+			clock:    [3]int{2, 0, 0},
+			loc:      ny,
+			expected: time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), // 03:00 EDT
+		},
+		{
+			name:     "should resolve the end of a gap",
+			date:     [3]int{2026, 3, 8},
+			clock:    [3]int{3, 0, 0},
+			loc:      ny,
+			expected: time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), // 03:00 EDT
+		},
+		{
+			name:     "should resolve the time before a gap",
+			date:     [3]int{2026, 3, 8},
+			clock:    [3]int{1, 59, 59},
+			loc:      ny,
+			expected: time.Date(2026, 3, 8, 6, 59, 59, 0, time.UTC), // 01:59:59 EST
+		},
+		{
+			name:     "should resolve a time repeated by a backward transition to its first occurrence",
+			date:     [3]int{2026, 11, 1},
+			clock:    [3]int{1, 30, 0},
+			loc:      ny,
+			expected: time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+		},
+		{
+			name:     "should resolve the time after a repeated hour",
+			date:     [3]int{2026, 11, 1},
+			clock:    [3]int{2, 0, 0},
+			loc:      ny,
+			expected: time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC), // 02:00 EST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := wallTime(tt.date[0], time.Month(tt.date[1]), tt.date[2], tt.clock[0], tt.clock[1], tt.clock[2], tt.loc)
+			assert.True(t, tt.expected.Equal(result), "expected %v but got %v", tt.expected, result)
+			assert.Equal(t, tt.loc, result.Location())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// cronSearchDays bounds the search for the next occurrence of a cron schedule.
+// Eight years cover every combination of weekday and leap day.
+const cronSearchDays = 8 * 366
+
+// CronSchedule is a parsed cron expression.
+// Each field is a bit set of the allowed values, e.g. bit 0 of Minutes is minute 0.
+// Occurrences are wall clock times in the location of the time passed to Next.
+type CronSchedule struct {
+	Minutes     uint64 // 0-59
+	Hours       uint32 // 0-23
+	DaysOfMonth uint32 // 1-31
+	Months      uint16 // 1-12
+	DaysOfWeek  uint8  // 0-6, 0=Sunday
+	// AnyDayOfMonth and AnyDayOfWeek record whether the day fields were "*".
+	// If both are restricted, a day matching either of them matches, as in Vixie cron.
+	AnyDayOfMonth bool
+	AnyDayOfWeek  bool
+}
+
+var _ Recurrence = CronSchedule{}
+
+func (c CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	day := civilDate(t)
+	for i := 0; i < cronSearchDays; i++ {
+		d := day.AddDate(0, 0, i)
+		if !c.matchesDay(d) {
+			continue
+		}
+		// On the day of t, wall clock times before that of t are not after t, even around a transition,
+		// so the search starts at the minute of t.
+		first := i == 0
+		for h := 0; h < 24; h++ {
+			if c.Hours&(1<<h) == 0 || first && h < t.Hour() {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if c.Minutes&(1<<m) == 0 || first && h == t.Hour() && m < t.Minute() {
+					continue
+				}
+				occ := wallTime(d.Year(), d.Month(), d.Day(), h, m, 0, loc)
+				if occ.After(t) {
+					return occ
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c CronSchedule) matchesDay(d time.Time) bool {
+	if c.Months&(1<<d.Month()) == 0 {
+		return false
+	}
+	dom := c.DaysOfMonth&(1<<d.Day()) != 0
+	dow := c.DaysOfWeek&(1<<d.Weekday()) != 0
+	if c.AnyDayOfMonth || c.AnyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cronBits returns a bit set with the given values.
+func cronBits(values ...int) uint64 {
+	var bits uint64
+	for _, v := range values {
+		bits |= 1 << v
+	}
+	return bits
+}
+
+// cronRange returns a bit set with all values from min to max.
+func cronRange(min, max int) uint64 {
+	var bits uint64
+	for v := min; v <= max; v++ {
+		bits |= 1 << v
+	}
+	return bits
+}
+
+func dailyCron(hour, minute int) CronSchedule {
+	return CronSchedule{
+		Minutes:       cronBits(minute),
+		Hours:         uint32(cronBits(hour)),
+		DaysOfMonth:   uint32(cronRange(1, 31)),
+		Months:        uint16(cronRange(1, 12)),
+		DaysOfWeek:    uint8(cronRange(0, 6)),
+		AnyDayOfMonth: true,
+		AnyDayOfWeek:  true,
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+
+	firstMondayOrFifteenth := dailyCron(9, 0)
+	firstMondayOrFifteenth.DaysOfMonth = uint32(cronBits(15))
+	firstMondayOrFifteenth.DaysOfWeek = uint8(cronBits(int(time.Monday)))
+	firstMondayOrFifteenth.AnyDayOfMonth = false
+	firstMondayOrFifteenth.AnyDayOfWeek = false
+
+	mondays := dailyCron(9, 0)
+	mondays.DaysOfWeek = uint8(cronBits(int(time.Monday)))
+	mondays.AnyDayOfWeek = false
+
+	leapDay := dailyCron(0, 0)
+	leapDay.DaysOfMonth = uint32(cronBits(29))
+	leapDay.Months = uint16(cronBits(2))
+	leapDay.AnyDayOfMonth = false
+
+	impossible := dailyCron(0, 0)
+	impossible.DaysOfMonth = uint32(cronBits(30))
+	impossible.Months = uint16(cronBits(2))
+	impossible.AnyDayOfMonth = false
+
+	everyQuarterHour := dailyCron(0, 0)
+	everyQuarterHour.Hours = uint32(cronRange(0, 23))
+	everyQuarterHour.Minutes = cronBits(0, 15, 30, 45)
+
+	tests := []struct {
+		name     string
+		schedule CronSchedule
+		time     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "should return the occurrence later on the same day",
+			schedule: dailyCron(9, 0),
+			time:     time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should return the occurrence on the next day",
+			schedule: dailyCron(9, 0),
+			time:     time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should return an occurrence strictly after the time",
+			schedule: dailyCron(9, 0),
+			time:     time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should return the next quarter hour",
+			schedule: everyQuarterHour,
+			time:     time.Date(2026, 1, 5, 23, 50, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should return the next quarter hour within the hour",
+			schedule: everyQuarterHour,
+			time:     time.Date(2026, 1, 5, 10, 20, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "should match weekdays",
+			schedule: mondays,
+			time:     time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), // Tuesday
+			expected: time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should match either day field if both are restricted",
+			schedule: firstMondayOrFifteenth,
+			time:     time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC), // Tuesday
+			expected: time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC), // Thursday
+		},
+		{
+			name:     "should find the next leap day",
+			schedule: leapDay,
+			time:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "should return zero time for impossible dates",
+			schedule: impossible,
+			time:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Time{},
+		},
+		{
+			name:     "should keep the wall clock time across a forward transition",
+			schedule: dailyCron(9, 0),
+			time:     time.Date(2026, 3, 7, 10, 0, 0, 0, ny),
+			expected: time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC), // 09:00 EDT
+		},
+		{
+			name:     "should keep the wall clock time across a backward transition",
+			schedule: dailyCron(9, 0),
+			time:     time.Date(2026, 10, 31, 10, 0, 0, 0, ny),
+			expected: time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC), // 09:00 EST
+		},
+		{
+			name:     "should move an occurrence skipped by a forward transition to the transition",
+			schedule: dailyCron(2, 30),
+			time:     time.Date(2026, 3, 7, 10, 0, 0, 0, ny),
+			expected: time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), // 03:00 EDT
+		},
+		{
+			name:     "should run an occurrence repeated by a backward transition once",
+			schedule: dailyCron(1, 30),
+			time:     time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny), // 01:30 EDT
+			expected: time.Date(2026, 11, 2, 6, 30, 0, 0, time.UTC),        // 01:30 EST on the next day
+		},
+		{
+			name:     "should skip quarter hours repeated by a backward transition",
+			schedule: everyQuarterHour,
+			time:     time.Date(2026, 11, 1, 6, 20, 0, 0, time.UTC).In(ny), // 01:20 EST
+			expected: time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),         // 02:00 EST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.schedule.Next(tt.time)
+			assert.True(t, tt.expected.Equal(result), "expected %v but got %v", tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateRangeRule is active between two absolute points in time, e.g. for one-off detox periods.
+// If Daily is set, the rule is further restricted to the window From-To on each day inside the range.
+type DateRangeRule struct {
+	Op    BlockOps
+	Start time.Time // inclusive
+	End   time.Time // exclusive
+	Daily bool
+	From  time.Time // inclusive, only used if Daily is set
+	To    time.Time // exclusive, only used if Daily is set
+}
+
+func NewDateRangeRule(ops string, start, end time.Time) (BlockRule, error) {
+	r := DateRangeRule{
+		Op:    BlockOps(ops),
+		Start: start,
+		End:   end,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to create date range rule: %w", err)
+	}
+	return r, nil
+}
+
+func NewDailyDateRangeRule(ops string, start, end, from, to time.Time) (BlockRule, error) {
+	r := DateRangeRule{
+		Op:    BlockOps(ops),
+		Start: start,
+		End:   end,
+		Daily: true,
+		From:  from,
+		To:    to,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to create date range rule: %w", err)
+	}
+	return r, nil
+}
+
+func (s DateRangeRule) Validate() error {
+	if err := s.Op.Validate(); err != nil {
+		return fmt.Errorf("invalid ops: %w", err)
+	}
+	if s.Start.IsZero() || s.End.IsZero() {
+		return fmt.Errorf("start and end must be set")
+	}
+	if !s.Start.Before(s.End) {
+		return fmt.Errorf("start must be before end")
+	}
+	return nil
+}
+
+func (s DateRangeRule) Ops() BlockOps {
+	return s.Op
+}
+
+func (s DateRangeRule) IsActive(t time.Time) bool {
+	if !(Window{Start: s.Start, End: s.End}).contains(t) {
+		return false
+	}
+	if s.Daily {
+		return dailyActive(s.From, s.To, t, nil)
+	}
+	return true
+}
+
+func (s DateRangeRule) Windows(from, to time.Time) []Window {
+	w, ok := Window{Start: s.Start, End: s.End}.clip(from, to)
+	if !ok {
+		return nil
+	}
+	if s.Daily {
+		return dailyWindows(s.From, s.To, w.Start.In(from.Location()), w.End, nil)
+	}
+	return []Window{w}
+}
+
+func (s DateRangeRule) NextChange(t time.Time) time.Time {
+	if !t.Before(s.End) {
+		return time.Time{}
+	}
+	if t.Before(s.Start) {
+		start := s.Start.In(t.Location())
+		if !s.Daily || dailyActive(s.From, s.To, start, nil) {
+			return s.Start
+		}
+		t = start
+	} else if !s.Daily {
+		return s.End
+	}
+	if c := dailyNextChange(s.From, s.To, t, nil); !c.IsZero() && c.Before(s.End) {
+		return c
+	}
+	if dailyActive(s.From, s.To, t, nil) {
+		return s.End
+	}
+	return time.Time{}
+}
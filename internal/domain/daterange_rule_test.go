@@ -0,0 +1,283 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRangeRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      DateRangeRule
+		expectErr bool
+	}{
+		{
+			name: "should validate correct rule",
+			rule: DateRangeRule{
+				Op:    BlockOpsBlock,
+				Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			},
+			expectErr: false,
+		},
+		{
+			name: "should validate correct rule with daily window",
+			rule: DateRangeRule{
+				Op:    BlockOpsBlock,
+				Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+				Daily: true,
+				From:  time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+				To:    time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC),
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when start is after end",
+			rule: DateRangeRule{
+				Op:    BlockOpsBlock,
+				Start: time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when start equals end",
+			rule: DateRangeRule{
+				Op:    BlockOpsBlock,
+				Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when end is not set",
+			rule: DateRangeRule{
+				Op:    BlockOpsBlock,
+				Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for invalid ops",
+			rule: DateRangeRule{
+				Op:    "invalid",
+				Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+			}
+		})
+	}
+}
+
+func TestDateRangeRule_IsActive(t *testing.T) {
+	t.Parallel()
+
+	examWeek := DateRangeRule{
+		Op:    BlockOpsBlock,
+		Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+	}
+	examWeekDaytime := examWeek
+	examWeekDaytime.Daily = true
+	examWeekDaytime.From = time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	examWeekDaytime.To = time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     DateRangeRule
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should return true at the start of the range",
+			rule:     examWeek,
+			time:     time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should return true inside the range",
+			rule:     examWeek,
+			time:     time.Date(2026, 11, 4, 21, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should return false at the end of the range",
+			rule:     examWeek,
+			time:     time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should return false before the range",
+			rule:     examWeek,
+			time:     time.Date(2026, 10, 31, 23, 59, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should compare instants regardless of location",
+			rule:     examWeek,
+			time:     time.Date(2026, 11, 1, 8, 0, 0, 0, time.FixedZone("JST", 9*60*60)), // 2026-10-31T23:00Z
+			expected: false,
+		},
+		{
+			name:     "should return true inside the daily window in the range",
+			rule:     examWeekDaytime,
+			time:     time.Date(2026, 11, 4, 10, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should return false outside the daily window in the range",
+			rule:     examWeekDaytime,
+			time:     time.Date(2026, 11, 4, 21, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should return false inside the daily window outside the range",
+			rule:     examWeekDaytime,
+			time:     time.Date(2026, 11, 9, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.IsActive(tt.time))
+		})
+	}
+}
+
+func TestDateRangeRule_Windows(t *testing.T) {
+	t.Parallel()
+
+	examWeek := DateRangeRule{
+		Op:    BlockOpsBlock,
+		Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+	}
+	examWeekDaytime := examWeek
+	examWeekDaytime.Daily = true
+	examWeekDaytime.From = time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	examWeekDaytime.To = time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     DateRangeRule
+		from     time.Time
+		to       time.Time
+		expected []Window
+	}{
+		{
+			name: "should return the range clipped to the window",
+			rule: examWeek,
+			from: time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:     "should return nothing outside the range",
+			rule:     examWeek,
+			from:     time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC),
+			expected: nil,
+		},
+		{
+			name: "should return daily windows inside the range",
+			rule: examWeekDaytime,
+			from: time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2026, 11, 7, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 11, 7, 17, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Windows(tt.from, tt.to))
+		})
+	}
+}
+
+func TestDateRangeRule_NextChange(t *testing.T) {
+	t.Parallel()
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rule     DateRangeRule
+		t        time.Time
+		expected time.Time
+	}{
+		{
+			name:     "should return the start before the range",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(8, 0)},
+			t:        at(1, 0),
+			expected: at(5, 0),
+		},
+		{
+			name:     "should return the end within the range",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(8, 0)},
+			t:        at(6, 0),
+			expected: at(8, 0),
+		},
+		{
+			name:     "should return zero after the range",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(8, 0)},
+			t:        at(8, 0),
+			expected: time.Time{},
+		},
+		{
+			name:     "should return the first daily window in the range",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(8, 0), Daily: true, From: clock(9), To: clock(17)},
+			t:        at(1, 0),
+			expected: at(5, 9),
+		},
+		{
+			name:     "should return the end of the current daily window",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(8, 0), Daily: true, From: clock(9), To: clock(17)},
+			t:        at(6, 10),
+			expected: at(6, 17),
+		},
+		{
+			name:     "should return the end of the range cutting a daily window",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(7, 12), Daily: true, From: clock(9), To: clock(17)},
+			t:        at(7, 10),
+			expected: at(7, 12),
+		},
+		{
+			name:     "should return zero without further daily windows in the range",
+			rule:     DateRangeRule{Op: BlockOpsBlock, Start: at(5, 0), End: at(7, 12), Daily: true, From: clock(13), To: clock(17)},
+			t:        at(7, 11),
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.NextChange(tt.t))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is a block window, optionally repeated by Recurrence.
+type Event struct {
+	Start    time.Time
+	Duration time.Duration
+	// Recurrence yields further starts of the event. It is nil for single events.
+	Recurrence Recurrence
+	// ExDates are excluded starts, which can include Start itself.
+	ExDates []time.Time
+}
+
+// EventSource provides the current list of events, e.g. from a calendar file.
+type EventSource interface {
+	Events() []Event
+}
+
+// EventRule is active during any event of Source.
+type EventRule struct {
+	Op     BlockOps
+	Source EventSource
+}
+
+func NewEventRule(ops string, source EventSource) (BlockRule, error) {
+	r := EventRule{
+		Op:     BlockOps(ops),
+		Source: source,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to create event rule: %w", err)
+	}
+	return r, nil
+}
+
+func (s EventRule) Validate() error {
+	if err := s.Op.Validate(); err != nil {
+		return fmt.Errorf("invalid ops: %w", err)
+	}
+	if s.Source == nil {
+		return fmt.Errorf("event source cannot be empty")
+	}
+	return nil
+}
+
+func (s EventRule) Ops() BlockOps {
+	return s.Op
+}
+
+func (s EventRule) IsActive(t time.Time) bool {
+	for _, e := range s.Source.Events() {
+		if e.IsActive(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Event) IsActive(t time.Time) bool {
+	if !e.isExcluded(e.Start) && (Window{Start: e.Start, End: e.Start.Add(e.Duration)}).contains(t) {
+		return true
+	}
+	if e.Recurrence == nil {
+		return false
+	}
+	return recurrenceActive(e.Recurrence, e.Duration, t, e.isExcluded)
+}
+
+func (e Event) isExcluded(start time.Time) bool {
+	for _, ex := range e.ExDates {
+		if ex.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s EventRule) NextChange(t time.Time) time.Time {
+	return nextChangeInWindows(s.Windows, t)
+}
+
+func (s EventRule) Windows(from, to time.Time) []Window {
+	var ws []Window
+	for _, e := range s.Source.Events() {
+		ws = append(ws, e.Windows(from, to)...)
+	}
+	return mergeWindows(ws)
+}
+
+func (e Event) Windows(from, to time.Time) []Window {
+	var ws []Window
+	if !e.isExcluded(e.Start) {
+		ws = appendClipped(ws, Window{Start: e.Start, End: e.Start.Add(e.Duration)}, from, to)
+	}
+	if e.Recurrence != nil {
+		ws = append(ws, recurrenceWindows(e.Recurrence, e.Duration, from, to, e.isExcluded)...)
+	}
+	return ws
+}
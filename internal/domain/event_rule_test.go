@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticEvents []Event
+
+func (s staticEvents) Events() []Event {
+	return s
+}
+
+func TestEventRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      EventRule
+		expectErr bool
+	}{
+		{
+			name:      "should validate correct rule",
+			rule:      EventRule{Op: BlockOpsBlock, Source: staticEvents{}},
+			expectErr: false,
+		},
+		{
+			name:      "should return error without source",
+			rule:      EventRule{Op: BlockOpsBlock},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for invalid ops",
+			rule:      EventRule{Op: "invalid", Source: staticEvents{}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+			}
+		})
+	}
+}
+
+func TestEventRule_IsActive(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	single := Event{Start: monday, Duration: 2 * time.Hour}
+	weekly := Event{
+		Start:      monday,
+		Duration:   2 * time.Hour,
+		Recurrence: RRule{Freq: FrequencyWeekly, Interval: 1, DTStart: monday, WeekStart: time.Monday},
+		ExDates:    []time.Time{monday.AddDate(0, 0, 7)},
+	}
+	firstExcluded := Event{
+		Start:      monday,
+		Duration:   time.Hour,
+		Recurrence: RRule{Freq: FrequencyDaily, Interval: 1, Count: 3, DTStart: monday, WeekStart: time.Monday},
+		ExDates:    []time.Time{monday},
+	}
+
+	tests := []struct {
+		name     string
+		events   staticEvents
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should return true during a single event",
+			events:   staticEvents{single},
+			time:     monday.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "should return false at the end of a single event",
+			events:   staticEvents{single},
+			time:     monday.Add(2 * time.Hour),
+			expected: false,
+		},
+		{
+			name:     "should return false without events",
+			events:   staticEvents{},
+			time:     monday,
+			expected: false,
+		},
+		{
+			name:     "should return true during a recurrence",
+			events:   staticEvents{weekly},
+			time:     monday.AddDate(0, 0, 14).Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "should return false during an excluded recurrence",
+			events:   staticEvents{weekly},
+			time:     monday.AddDate(0, 0, 7).Add(time.Hour),
+			expected: false,
+		},
+		{
+			name:     "should return false during an excluded first occurrence",
+			events:   staticEvents{firstExcluded},
+			time:     monday.Add(30 * time.Minute),
+			expected: false,
+		},
+		{
+			name:     "should return true after an excluded first occurrence",
+			events:   staticEvents{firstExcluded},
+			time:     monday.AddDate(0, 0, 1).Add(30 * time.Minute),
+			expected: true,
+		},
+		{
+			name:     "should return false between recurrences",
+			events:   staticEvents{weekly},
+			time:     monday.AddDate(0, 0, 1),
+			expected: false,
+		},
+		{
+			name:     "should return true if any event is active",
+			events:   staticEvents{single, Event{Start: monday.AddDate(0, 0, 7), Duration: time.Hour}},
+			time:     monday.AddDate(0, 0, 7),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := EventRule{Op: BlockOpsBlock, Source: tt.events}
+			assert.Equal(t, tt.expected, rule.IsActive(tt.time))
+		})
+	}
+}
+
+func TestEventRule_Windows(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	weekly := Event{
+		Start:      monday,
+		Duration:   2 * time.Hour,
+		Recurrence: RRule{Freq: FrequencyWeekly, Interval: 1, DTStart: monday, WeekStart: time.Monday},
+		ExDates:    []time.Time{monday.AddDate(0, 0, 7)},
+	}
+	overlapping := Event{Start: monday.Add(time.Hour), Duration: 2 * time.Hour}
+
+	rule := EventRule{Op: BlockOpsBlock, Source: staticEvents{weekly, overlapping}}
+	windows := rule.Windows(monday, monday.AddDate(0, 0, 15))
+	assert.Equal(t, []Window{
+		{Start: monday, End: monday.Add(3 * time.Hour)},
+		{Start: monday.AddDate(0, 0, 14), End: monday.AddDate(0, 0, 14).Add(2 * time.Hour)},
+	}, windows)
+
+	firstExcluded := Event{
+		Start:      monday,
+		Duration:   time.Hour,
+		Recurrence: RRule{Freq: FrequencyDaily, Interval: 1, Count: 3, DTStart: monday, WeekStart: time.Monday},
+		ExDates:    []time.Time{monday},
+	}
+	rule = EventRule{Op: BlockOpsBlock, Source: staticEvents{firstExcluded}}
+	assert.Equal(t, []Window{
+		{Start: monday.AddDate(0, 0, 1), End: monday.AddDate(0, 0, 1).Add(time.Hour)},
+		{Start: monday.AddDate(0, 0, 2), End: monday.AddDate(0, 0, 2).Add(time.Hour)},
+	}, rule.Windows(monday, monday.AddDate(0, 0, 7)), "expected no window for an excluded first occurrence")
+}
+
+func TestEventRule_NextChange(t *testing.T) {
+	t.Parallel()
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	rule := EventRule{Op: BlockOpsBlock, Source: staticEvents{
+		{Start: at(5, 9), Duration: 2 * time.Hour},
+		{Start: at(5, 11), Duration: time.Hour},
+		{Start: at(20, 9), Duration: time.Hour},
+	}}
+	assert.Equal(t, at(5, 9), rule.NextChange(at(5, 0)), "expected the start of the first event")
+	assert.Equal(t, at(5, 12), rule.NextChange(at(5, 9)), "expected the end of touching events")
+	assert.Equal(t, at(20, 9), rule.NextChange(at(5, 12)), "expected the start of an event more than a week later")
+	assert.True(t, rule.NextChange(at(20, 10)).IsZero(), "expected no change after the last event")
+}
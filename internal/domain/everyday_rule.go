@@ -2,21 +2,24 @@ package domain
 
 import (
 	"fmt"
-	"log/slog"
 	"time"
 )
 
+// EveryDayRule is active between the wall clock times of From and To every day.
+// If To is not later than From, the window continues into the next day.
 type EveryDayRule struct {
-	Op   BlockOps
-	From time.Time // inclusive
-	To   time.Time // exclusive
+	Op         BlockOps
+	From       time.Time // inclusive
+	To         time.Time // exclusive
+	Exceptions Exceptions
 }
 
-func NewEveryDayRule(ops string, from, to time.Time) (BlockRule, error) {
+func NewEveryDayRule(ops string, from, to time.Time, exceptions Exceptions) (BlockRule, error) {
 	r := EveryDayRule{
-		Op:   BlockOps(ops),
-		From: from,
-		To:   to,
+		Op:         BlockOps(ops),
+		From:       from,
+		To:         to,
+		Exceptions: exceptions,
 	}
 	if err := r.Validate(); err != nil {
 		return nil, fmt.Errorf("failed to create everyday rule: %w", err)
@@ -28,11 +31,7 @@ func (s EveryDayRule) Validate() error {
 	if err := s.Op.Validate(); err != nil {
 		return fmt.Errorf("invalid ops: %w", err)
 	}
-	if s.From.After(s.To) {
-		return fmt.Errorf("from time must be before to time")
-	}
 	return nil
-
 }
 
 func (s EveryDayRule) Ops() BlockOps {
@@ -40,11 +39,17 @@ func (s EveryDayRule) Ops() BlockOps {
 }
 
 func (s EveryDayRule) IsActive(t time.Time) bool {
-	from := time.Date(t.Year(), t.Month(), t.Day(), s.From.Hour(), s.From.Minute(), 0, 0, t.Location())
-	to := time.Date(t.Year(), t.Month(), t.Day(), s.To.Hour(), s.To.Minute(), 0, 0, t.Location())
-	slog.Info("from/to", "from", from, "to", to, "t", t)
-	if from.After(t) || to.Before(t) {
-		return false
-	}
-	return true
+	return dailyActive(s.From, s.To, t, s.appliesOn)
+}
+
+func (s EveryDayRule) appliesOn(day time.Time) bool {
+	return s.Exceptions.applies(day, true)
+}
+
+func (s EveryDayRule) Windows(from, to time.Time) []Window {
+	return dailyWindows(s.From, s.To, from, to, s.appliesOn)
+}
+
+func (s EveryDayRule) NextChange(t time.Time) time.Time {
+	return dailyNextChange(s.From, s.To, t, s.appliesOn)
 }
@@ -25,11 +25,20 @@ func TestEveryDayRule_Validate(t *testing.T) {
 			expectErr: false,
 		},
 		{
-			name: "should return error when from time is after to time",
+			name: "should validate overnight rule",
 			rule: EveryDayRule{
 				Op:   BlockOpsBlock,
-				From: time.Date(0, 1, 1, 23, 0, 0, 0, time.UTC),
-				To:   time.Date(0, 1, 1, 22, 59, 59, 999999999, time.UTC),
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error for invalid ops",
+			rule: EveryDayRule{
+				Op:   "invalid",
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 23, 0, 0, 0, time.UTC),
 			},
 			expectErr: true,
 		},
@@ -75,6 +84,89 @@ func TestEveryDayRule_IsActive(t *testing.T) {
 			time:     time.Date(2025, 1, 1, 21, 30, 0, 0, time.UTC),
 			expected: false,
 		},
+		{
+			name: "should return false at exactly the end of the range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+			},
+			time:     time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "should respect seconds of the range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 9, 0, 30, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+			},
+			time:     time.Date(2025, 1, 1, 9, 0, 15, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "should return true before midnight in an overnight range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+			},
+			time:     time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name: "should return true after midnight in an overnight range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+			},
+			time:     time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name: "should return false during the day outside an overnight range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+			},
+			time:     time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "should return false on skipped dates",
+			rule: EveryDayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "should skip the part after midnight of an overnight range started on a skipped date",
+			rule: EveryDayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "should not skip the part after midnight of an overnight range started before a skipped date",
+			rule: EveryDayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -84,3 +176,137 @@ func TestEveryDayRule_IsActive(t *testing.T) {
 		})
 	}
 }
+
+func TestEveryDayRule_Windows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rule     EveryDayRule
+		from     time.Time
+		to       time.Time
+		expected []Window
+	}{
+		{
+			name: "should return a window for every day",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+			from: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "should clip windows to the range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+			from: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "should include overnight windows started before the range",
+			rule: EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+			},
+			from: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 1, 6, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Windows(tt.from, tt.to))
+		})
+	}
+}
+
+func TestEveryDayRule_NextChange(t *testing.T) {
+	t.Parallel()
+
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rule     EveryDayRule
+		t        time.Time
+		expected time.Time
+	}{
+		{
+			name:     "should return the start of the window later today",
+			rule:     EveryDayRule{Op: BlockOpsBlock, From: clock(9), To: clock(17)},
+			t:        at(2, 8),
+			expected: at(2, 9),
+		},
+		{
+			name:     "should return the end of the current window",
+			rule:     EveryDayRule{Op: BlockOpsBlock, From: clock(9), To: clock(17)},
+			t:        at(2, 9),
+			expected: at(2, 17),
+		},
+		{
+			name:     "should return the start of the window tomorrow at the end of today's",
+			rule:     EveryDayRule{Op: BlockOpsBlock, From: clock(9), To: clock(17)},
+			t:        at(2, 17),
+			expected: at(3, 9),
+		},
+		{
+			name:     "should return the end of an overnight window started yesterday",
+			rule:     EveryDayRule{Op: BlockOpsBlock, From: clock(22), To: clock(6)},
+			t:        at(2, 1),
+			expected: at(2, 6),
+		},
+		{
+			name: "should skip exception dates",
+			rule: EveryDayRule{
+				Op: BlockOpsBlock, From: clock(9), To: clock(17),
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))},
+			},
+			t:        at(2, 17),
+			expected: at(4, 9),
+		},
+		{
+			name: "should return the end of whole-day windows on consecutive days at the first skipped day",
+			rule: EveryDayRule{
+				Op: BlockOpsBlock, From: clock(0), To: clock(0),
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC))},
+			},
+			t:        at(2, 12),
+			expected: at(5, 0),
+		},
+		{
+			name:     "should return zero for a rule that is always active",
+			rule:     EveryDayRule{Op: BlockOpsBlock, From: clock(0), To: clock(0)},
+			t:        at(2, 12),
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.NextChange(tt.t))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// DateSet is a set of calendar dates such as public holidays.
+type DateSet map[string]struct{}
+
+func NewDateSet(dates ...time.Time) DateSet {
+	s := DateSet{}
+	for _, d := range dates {
+		s[d.Format(time.DateOnly)] = struct{}{}
+	}
+	return s
+}
+
+// Contains reports whether the calendar date of t in its location is in the set.
+func (s DateSet) Contains(t time.Time) bool {
+	_, ok := s[t.Format(time.DateOnly)]
+	return ok
+}
+
+// Union returns a set with the dates of both sets. The sets are not modified.
+func (s DateSet) Union(other DateSet) DateSet {
+	if len(other) == 0 {
+		return s
+	}
+	if len(s) == 0 {
+		return other
+	}
+	u := make(DateSet, len(s)+len(other))
+	for d := range s {
+		u[d] = struct{}{}
+	}
+	for d := range other {
+		u[d] = struct{}{}
+	}
+	return u
+}
+
+// Exceptions adjust the dates on which a daily rule applies.
+// A window crossing midnight belongs to the date it starts on.
+type Exceptions struct {
+	// Skip is the set of dates on which the rule does not apply, e.g. public holidays.
+	Skip DateSet
+	// Force is the set of dates on which the rule applies even if it otherwise would not.
+	// It takes precedence over Skip.
+	Force DateSet
+}
+
+// applies reports whether a rule applies on day, given whether it normally would.
+func (e Exceptions) applies(day time.Time, normally bool) bool {
+	if e.Force.Contains(day) {
+		return true
+	}
+	return normally && !e.Skip.Contains(day)
+}
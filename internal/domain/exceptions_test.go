@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateSet_Contains(t *testing.T) {
+	t.Parallel()
+
+	s := NewDateSet(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.True(t, s.Contains(time.Date(2026, 1, 1, 23, 59, 0, 0, time.UTC)))
+	assert.False(t, s.Contains(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)))
+	// should use the calendar date in the location of the time
+	assert.True(t, s.Contains(time.Date(2026, 1, 1, 8, 0, 0, 0, time.FixedZone("JST", 9*60*60))))
+	assert.False(t, s.Contains(time.Date(2026, 1, 1, 8, 0, 0, 0, time.FixedZone("JST", 9*60*60)).UTC()))
+	assert.False(t, DateSet(nil).Contains(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestDateSet_Union(t *testing.T) {
+	t.Parallel()
+
+	a := NewDateSet(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewDateSet(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	u := a.Union(b)
+
+	assert.Len(t, u, 2)
+	assert.Len(t, a, 1, "union should not modify the receiver")
+}
+
+func TestExceptions_applies(t *testing.T) {
+	t.Parallel()
+
+	holiday := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	workday := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	e := Exceptions{
+		Skip:  NewDateSet(holiday),
+		Force: NewDateSet(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name     string
+		e        Exceptions
+		day      time.Time
+		normally bool
+		expected bool
+	}{
+		{name: "should apply normally without exceptions", e: Exceptions{}, day: holiday, normally: true, expected: true},
+		{name: "should not apply when not applying normally", e: Exceptions{}, day: holiday, normally: false, expected: false},
+		{name: "should skip dates in skip", e: e, day: holiday, normally: true, expected: false},
+		{name: "should apply on other dates", e: e, day: workday, normally: true, expected: true},
+		{name: "should force dates in force", e: e, day: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), normally: false, expected: true},
+		{name: "should prefer force over skip", e: Exceptions{Skip: NewDateSet(holiday), Force: NewDateSet(holiday)}, day: holiday, normally: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.e.applies(tt.day, tt.normally))
+		})
+	}
+}
@@ -14,14 +14,39 @@ func NewHostsGenerator(blockers []Blocker) *HostsGenerator {
 	return &HostsGenerator{blockers: blockers}
 }
 
+// Blockers returns the blockers of the generator.
+func (g *HostsGenerator) Blockers() []Blocker {
+	return g.blockers
+}
+
 type HostsEntry struct {
-	IP     net.IP
+	IP net.IP
+	// IPv6 is the optional IPv6 address the domain is forwarded to in addition to IP.
+	IPv6   net.IP
 	Domain string
+	// IncludeSubdomains is set if all subdomains of Domain are blocked as well.
+	IncludeSubdomains bool
+	// Subdomains are the known subdomains of Domain which are blocked.
+	Subdomains []string
 }
 
+// String returns the hosts file line of Domain for IP.
 func (e HostsEntry) String() string {
-	//return e.IP.String() + " " + e.Domain
-	return e.Domain
+	return e.IP.String() + " " + e.Domain
+}
+
+// IPs returns IP followed by IPv6 if it is set.
+func (e HostsEntry) IPs() []net.IP {
+	if e.IPv6 == nil {
+		return []net.IP{e.IP}
+	}
+	return []net.IP{e.IP, e.IPv6}
+}
+
+// Names returns Domain followed by its known subdomains,
+// for output formats that cannot express a wildcard.
+func (e HostsEntry) Names() []string {
+	return append([]string{e.Domain}, e.Subdomains...)
 }
 
 func (g *HostsGenerator) Gen(t time.Time) []HostsEntry {
@@ -29,10 +54,76 @@ func (g *HostsGenerator) Gen(t time.Time) []HostsEntry {
 	for _, blocker := range g.blockers {
 		if blocker.IsBlocked(t) {
 			entries = append(entries, HostsEntry{
-				IP:     blocker.ForwardTo,
-				Domain: blocker.Domain,
+				IP:                blocker.ForwardTo,
+				IPv6:              blocker.ForwardToV6,
+				Domain:            blocker.Domain,
+				IncludeSubdomains: blocker.IncludeSubdomains,
+				Subdomains:        blocker.Subdomains,
 			})
 		}
 	}
 	return entries
 }
+
+// DomainWindows is the blocking schedule of a single domain.
+type DomainWindows struct {
+	Domain  string
+	Windows []Window
+}
+
+// Schedule returns the intervals in [from, to) in which each domain is blocked.
+// Windows of blockers for the same domain are merged. Domains are listed in the order of the blockers.
+func (g *HostsGenerator) Schedule(from, to time.Time) []DomainWindows {
+	var schedule []DomainWindows
+	index := map[string]int{}
+	for _, blocker := range g.blockers {
+		ws := blocker.Windows(from, to)
+		i, ok := index[blocker.Domain]
+		if !ok {
+			index[blocker.Domain] = len(schedule)
+			schedule = append(schedule, DomainWindows{Domain: blocker.Domain, Windows: ws})
+			continue
+		}
+		schedule[i].Windows = mergeWindows(append(schedule[i].Windows, ws...))
+	}
+	return schedule
+}
+
+// LastChange returns the latest time in (t - lookback, t] at which a blocker started or stopped blocking,
+// or the zero time if there was none.
+func (g *HostsGenerator) LastChange(t time.Time, lookback time.Duration) time.Time {
+	var last time.Time
+	g.eachChange(t.Add(-lookback), t.Add(time.Nanosecond), func(c time.Time) {
+		if c.After(last) {
+			last = c
+		}
+	})
+	return last
+}
+
+// NextChange returns the earliest time in (t, t + horizon) at which a blocker starts or stops blocking,
+// or the zero time if there is none.
+func (g *HostsGenerator) NextChange(t time.Time, horizon time.Duration) time.Time {
+	var next time.Time
+	for _, blocker := range g.blockers {
+		if c := blocker.NextChange(t, horizon); !c.IsZero() && (next.IsZero() || c.Before(next)) {
+			next = c
+		}
+	}
+	return next
+}
+
+// eachChange calls f with every time in (from, to) at which a blocker starts or stops blocking.
+func (g *HostsGenerator) eachChange(from, to time.Time, f func(time.Time)) {
+	for _, blocker := range g.blockers {
+		for _, w := range blocker.Windows(from, to) {
+			// Windows are clipped to [from, to), so bounds at from and to are not changes.
+			if w.Start.After(from) {
+				f(w.Start)
+			}
+			if w.End.Before(to) {
+				f(w.End)
+			}
+		}
+	}
+}
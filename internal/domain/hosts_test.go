@@ -76,6 +76,29 @@ func TestHostsGenerator_Gen(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should carry subdomains and IPv6 addresses of active blockers",
+			blockers: []Blocker{
+				{
+					Domain:            "twitter.com",
+					IncludeSubdomains: true,
+					Subdomains:        []string{"mobile.twitter.com"},
+					ForwardTo:         net.IPv4(0, 0, 0, 0),
+					ForwardToV6:       net.IPv6unspecified,
+					Rules:             []BlockRule{&MockRule{Active: true}},
+				},
+			},
+			time: time.Date(2023, 10, 1, 7, 0, 0, 0, time.UTC),
+			expected: []HostsEntry{
+				{
+					Domain:            "twitter.com",
+					IP:                net.IPv4(0, 0, 0, 0),
+					IPv6:              net.IPv6unspecified,
+					IncludeSubdomains: true,
+					Subdomains:        []string{"mobile.twitter.com"},
+				},
+			},
+		},
 		{
 			name:     "should not generate entries for inactive blockers",
 			blockers: exampleBlocker(),
@@ -95,3 +118,154 @@ func TestHostsGenerator_Gen(t *testing.T) {
 		})
 	}
 }
+
+func TestHostsEntry_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.0.0.0 twitter.com", HostsEntry{IP: net.IPv4(0, 0, 0, 0), Domain: "twitter.com"}.String())
+}
+
+func TestHostsEntry_IPs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []net.IP{net.IPv4(0, 0, 0, 0)}, HostsEntry{IP: net.IPv4(0, 0, 0, 0)}.IPs())
+	assert.Equal(t,
+		[]net.IP{net.IPv4(0, 0, 0, 0), net.IPv6unspecified},
+		HostsEntry{IP: net.IPv4(0, 0, 0, 0), IPv6: net.IPv6unspecified}.IPs(),
+	)
+}
+
+func TestHostsEntry_Names(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"twitter.com"}, HostsEntry{Domain: "twitter.com"}.Names())
+	assert.Equal(t,
+		[]string{"twitter.com", "mobile.twitter.com", "api.twitter.com"},
+		HostsEntry{Domain: "twitter.com", Subdomains: []string{"mobile.twitter.com", "api.twitter.com"}}.Names(),
+	)
+}
+
+func TestHostsGenerator_Schedule(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC) // Monday
+	blockers := append(exampleBlocker(), Blocker{
+		Domain: "x.com",
+		Rules: []BlockRule{
+			EveryDayRule{
+				Op:   BlockOpsBlock,
+				From: time.Date(0, 1, 1, 16, 0, 0, 0, time.UTC),
+				To:   time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC),
+			},
+		},
+	})
+	generator := NewHostsGenerator(blockers)
+	schedule := generator.Schedule(from, from.AddDate(0, 0, 1))
+
+	at := func(hour, min int) time.Time {
+		return from.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+	assert.Equal(t, []DomainWindows{
+		{
+			Domain: "twitter.com",
+			Windows: []Window{
+				{Start: at(0, 0), End: at(5, 0)},
+				{Start: at(8, 0), End: at(18, 0)},
+				{Start: at(22, 0), End: at(23, 59)},
+			},
+		},
+		{
+			Domain: "x.com",
+			Windows: []Window{
+				{Start: at(0, 0), End: at(5, 0)},
+				{Start: at(10, 0), End: at(17, 0)},
+			},
+		},
+	}, schedule)
+}
+
+func TestHostsGenerator_LastChange(t *testing.T) {
+	t.Parallel()
+
+	generator := NewHostsGenerator(exampleBlocker())
+	monday := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		lookback time.Duration
+		expected time.Time
+	}{
+		{
+			name:     "should return the latest start or end of any blocker",
+			time:     at(12, 0),
+			lookback: 24 * time.Hour,
+			expected: at(10, 0), // x.com
+		},
+		{
+			name:     "should include a change at exactly the time",
+			time:     at(16, 0),
+			lookback: 24 * time.Hour,
+			expected: at(16, 0),
+		},
+		{
+			name:     "should return zero if nothing changed within the lookback",
+			time:     at(12, 0),
+			lookback: time.Hour,
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, generator.LastChange(tt.time, tt.lookback))
+		})
+	}
+}
+
+func TestHostsGenerator_NextChange(t *testing.T) {
+	t.Parallel()
+
+	generator := NewHostsGenerator(exampleBlocker())
+	monday := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		horizon  time.Duration
+		expected time.Time
+	}{
+		{
+			name:     "should return the earliest start or end of any blocker",
+			time:     at(12, 0),
+			horizon:  24 * time.Hour,
+			expected: at(16, 0), // x.com
+		},
+		{
+			name:     "should not return a change at exactly the time",
+			time:     at(16, 0),
+			horizon:  24 * time.Hour,
+			expected: at(18, 0),
+		},
+		{
+			name:     "should return zero if nothing changes within the horizon",
+			time:     at(12, 0),
+			horizon:  time.Hour,
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, generator.NextChange(tt.time, tt.horizon))
+		})
+	}
+
+	assert.True(t, NewHostsGenerator(nil).NextChange(at(12, 0), 24*time.Hour).IsZero(), "expected no change without blockers")
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Recurrence yields the start times of a recurring event.
+type Recurrence interface {
+	// Next returns the first occurrence strictly after t, or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// RecurrenceRule is active for Duration after each occurrence of Recurrence.
+// Duration is elapsed time, so a window spanning a daylight saving time transition
+// ends an hour earlier or later on the wall clock.
+type RecurrenceRule struct {
+	Op         BlockOps
+	Recurrence Recurrence
+	Duration   time.Duration
+}
+
+func NewRecurrenceRule(ops string, recurrence Recurrence, duration time.Duration) (BlockRule, error) {
+	r := RecurrenceRule{
+		Op:         BlockOps(ops),
+		Recurrence: recurrence,
+		Duration:   duration,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to create recurrence rule: %w", err)
+	}
+	return r, nil
+}
+
+func (s RecurrenceRule) Validate() error {
+	if err := s.Op.Validate(); err != nil {
+		return fmt.Errorf("invalid ops: %w", err)
+	}
+	if s.Recurrence == nil {
+		return fmt.Errorf("recurrence cannot be empty")
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	return nil
+}
+
+func (s RecurrenceRule) Ops() BlockOps {
+	return s.Op
+}
+
+func (s RecurrenceRule) IsActive(t time.Time) bool {
+	return recurrenceActive(s.Recurrence, s.Duration, t, nil)
+}
+
+func (s RecurrenceRule) Windows(from, to time.Time) []Window {
+	return recurrenceWindows(s.Recurrence, s.Duration, from, to, nil)
+}
+
+// NextChange returns the next occurrence if the rule is inactive at t. Otherwise it returns the end of
+// the occurrences overlapping each other from t on, or the zero time if they do not end within a year.
+func (s RecurrenceRule) NextChange(t time.Time) time.Time {
+	if !s.IsActive(t) {
+		return s.Recurrence.Next(t)
+	}
+	var end time.Time
+	limit := t.Add(changeSearchSpans[len(changeSearchSpans)-1])
+	for start := s.Recurrence.Next(t.Add(-s.Duration)); !start.IsZero() && !start.After(maxTime(t, end)); start = s.Recurrence.Next(start) {
+		if e := start.Add(s.Duration); e.After(end) {
+			end = e
+		}
+		if end.After(limit) {
+			return time.Time{}
+		}
+	}
+	return end
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrenceRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      RecurrenceRule
+		expectErr bool
+	}{
+		{
+			name:      "should validate correct rule",
+			rule:      RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(9, 0), Duration: time.Hour},
+			expectErr: false,
+		},
+		{
+			name:      "should return error without recurrence",
+			rule:      RecurrenceRule{Op: BlockOpsBlock, Duration: time.Hour},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for non-positive duration",
+			rule:      RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(9, 0)},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for invalid ops",
+			rule:      RecurrenceRule{Op: "invalid", Recurrence: dailyCron(9, 0), Duration: time.Hour},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_IsActive(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	firstMonday := RecurrenceRule{
+		Op: BlockOpsBlock,
+		Recurrence: RRule{
+			Freq:     FrequencyMonthly,
+			Interval: 1,
+			DTStart:  time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			ByDay:    []WeekdayNum{{Weekday: time.Monday, N: 1}},
+		},
+		Duration: 3 * time.Hour,
+	}
+	nightly := RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(1, 0), Duration: 2 * time.Hour}
+
+	tests := []struct {
+		name     string
+		rule     RecurrenceRule
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should return true at the start of an occurrence",
+			rule:     firstMonday,
+			time:     time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should return true during an occurrence",
+			rule:     firstMonday,
+			time:     time.Date(2026, 2, 2, 11, 59, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should return false at the end of an occurrence",
+			rule:     firstMonday,
+			time:     time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should return false on other Mondays",
+			rule:     firstMonday,
+			time:     time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should return false before dtstart",
+			rule:     firstMonday,
+			time:     time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should return true during an occurrence that started the previous day",
+			rule:     RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(23, 0), Duration: 8 * time.Hour},
+			time:     time.Date(2026, 1, 6, 6, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should measure duration as elapsed time across a backward transition",
+			rule:     nightly,
+			time:     time.Date(2026, 11, 1, 1, 30, 0, 0, time.UTC).Add(5 * time.Hour).In(ny), // second 01:30, 01:30 EST
+			expected: true,
+		},
+		{
+			name:     "should end after the elapsed duration across a backward transition",
+			rule:     nightly,
+			time:     time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC).In(ny), // 02:00 EST, two hours after 01:00 EDT
+			expected: false,
+		},
+		{
+			name:     "should measure duration as elapsed time across a forward transition",
+			rule:     nightly,
+			time:     time.Date(2026, 3, 8, 3, 30, 0, 0, ny), // 1.5 hours after 01:00 EST
+			expected: true,
+		},
+		{
+			name:     "should end after the elapsed duration across a forward transition",
+			rule:     nightly,
+			time:     time.Date(2026, 3, 8, 4, 0, 0, 0, ny), // 2 hours after 01:00 EST
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.IsActive(tt.time))
+		})
+	}
+}
+
+func TestRecurrenceRule_Windows(t *testing.T) {
+	t.Parallel()
+
+	rule := RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(23, 0), Duration: 8 * time.Hour}
+	windows := rule.Windows(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, []Window{
+		{Start: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 5, 7, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 6, 7, 0, 0, 0, time.UTC)},
+	}, windows)
+}
+
+func TestRecurrenceRule_NextChange(t *testing.T) {
+	t.Parallel()
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	rule := RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(23, 0), Duration: 8 * time.Hour}
+	assert.Equal(t, at(5, 23), rule.NextChange(at(5, 12)), "expected the next occurrence")
+	assert.Equal(t, at(6, 7), rule.NextChange(at(5, 23)), "expected the end of the current occurrence")
+
+	overlapping := RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(23, 0), Duration: 25 * time.Hour}
+	assert.True(t, overlapping.NextChange(at(5, 23)).IsZero(), "expected no change of overlapping occurrences")
+
+	chained := RecurrenceRule{Op: BlockOpsBlock, Recurrence: dailyCron(0, 0), Duration: 24 * time.Hour}
+	assert.True(t, chained.NextChange(at(5, 12)).IsZero(), "expected no change of touching occurrences")
+}
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// rruleMaxEmptyPeriods bounds the search for the next occurrence of a recurrence rule
+// whose periods stop producing occurrences. Daily leap day rules may skip eight years.
+const rruleMaxEmptyPeriods = 8 * 366
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is an entry of BYDAY such as "MO", "1MO" or "-1FR".
+type WeekdayNum struct {
+	Weekday time.Weekday
+	// N selects the n-th such weekday of the month, counting from the end if negative.
+	// 0 selects every such weekday.
+	N int
+}
+
+// RRule is a subset of the iCalendar recurrence rule (RFC 5545, section 3.3.10).
+// BYSECOND, BYMINUTE, BYHOUR, BYWEEKNO, BYYEARDAY and BYSETPOS are not supported.
+//
+// Every occurrence has the wall clock time of DTStart in the location of DTStart.
+// Dates before DTStart never occur, even if they match the rule.
+type RRule struct {
+	Freq     Frequency
+	Interval int
+	DTStart  time.Time
+	Until    time.Time // inclusive, optional
+	Count    int       // optional
+	// ByMonth, ByMonthDay and ByDay expand or limit the occurrences as in RFC 5545.
+	ByMonth    []time.Month
+	ByMonthDay []int // 1 to 31, or -1 to -31 counting from the end of the month
+	ByDay      []WeekdayNum
+	// WeekStart is the first day of the week (WKST). iCalendar's default is Monday.
+	WeekStart time.Weekday
+}
+
+var _ Recurrence = RRule{}
+
+func (r RRule) Validate() error {
+	switch r.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return fmt.Errorf("unsupported frequency: %s", r.Freq)
+	}
+	if r.Interval < 1 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if r.DTStart.IsZero() {
+		return fmt.Errorf("dtstart must be set")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("count and until cannot be used together")
+	}
+	for _, m := range r.ByMonth {
+		if m < time.January || m > time.December {
+			return fmt.Errorf("invalid month: %d", m)
+		}
+	}
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -31 || d > 31 {
+			return fmt.Errorf("invalid day of month: %d", d)
+		}
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday < time.Sunday || wd.Weekday > time.Saturday {
+			return fmt.Errorf("invalid weekday: %d", wd.Weekday)
+		}
+		if wd.N == 0 {
+			continue
+		}
+		if wd.N < -5 || wd.N > 5 {
+			return fmt.Errorf("invalid weekday ordinal: %d", wd.N)
+		}
+		if r.Freq != FrequencyMonthly && r.Freq != FrequencyYearly {
+			return fmt.Errorf("weekday ordinals are only supported for monthly and yearly rules")
+		}
+		if r.Freq == FrequencyYearly && len(r.ByMonth) == 0 {
+			return fmt.Errorf("weekday ordinals in yearly rules require bymonth")
+		}
+	}
+	return nil
+}
+
+func (r RRule) Next(t time.Time) time.Time {
+	interval := max(r.Interval, 1)
+	first := r.periodStart(0)
+
+	k := 0
+	if r.Count == 0 {
+		// Without COUNT, periods before t cannot affect the result and are skipped.
+		k = max(r.periodsUntil(first, t)/interval-1, 0)
+	}
+
+	n := 0
+	for empty := 0; empty < rruleMaxEmptyPeriods; k++ {
+		dates := r.expand(r.periodStart(k * interval))
+		if len(dates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+		for _, d := range dates {
+			occ := wallTime(d.Year(), d.Month(), d.Day(), r.DTStart.Hour(), r.DTStart.Minute(), r.DTStart.Second(), r.DTStart.Location())
+			if occ.Before(r.DTStart) {
+				continue
+			}
+			if !r.Until.IsZero() && occ.After(r.Until) {
+				return time.Time{}
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return time.Time{}
+			}
+			if occ.After(t) {
+				return occ
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// periodStart returns the first civil date of the k-th period after the one containing DTStart.
+func (r RRule) periodStart(k int) time.Time {
+	start := civilDate(r.DTStart)
+	switch r.Freq {
+	case FrequencyWeekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		return start.AddDate(0, 0, 7*k-offset)
+	case FrequencyMonthly:
+		return time.Date(start.Year(), start.Month()+time.Month(k), 1, 0, 0, 0, 0, time.UTC)
+	case FrequencyYearly:
+		return time.Date(start.Year()+k, time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return start.AddDate(0, 0, k)
+	}
+}
+
+// periodsUntil returns the number of whole periods between first and the period containing t.
+func (r RRule) periodsUntil(first, t time.Time) int {
+	d := civilDate(t.In(r.DTStart.Location()))
+	switch r.Freq {
+	case FrequencyWeekly:
+		return int(d.Sub(first).Hours()) / 24 / 7
+	case FrequencyMonthly:
+		return (d.Year()-first.Year())*12 + int(d.Month()) - int(first.Month())
+	case FrequencyYearly:
+		return d.Year() - first.Year()
+	default:
+		return int(d.Sub(first).Hours()) / 24
+	}
+}
+
+// expand returns the civil dates of the occurrences in the period starting at p, in order.
+func (r RRule) expand(p time.Time) []time.Time {
+	var dates []time.Time
+	switch r.Freq {
+	case FrequencyDaily:
+		if r.matchesMonth(p) && r.matchesMonthDay(p) && r.matchesWeekday(p) {
+			dates = append(dates, p)
+		}
+	case FrequencyWeekly:
+		for i := 0; i < 7; i++ {
+			d := p.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && d.Weekday() != r.DTStart.Weekday() {
+				continue
+			}
+			if r.matchesMonth(d) && r.matchesMonthDay(d) && r.matchesWeekday(d) {
+				dates = append(dates, d)
+			}
+		}
+	case FrequencyMonthly:
+		if r.matchesMonth(p) {
+			dates = r.expandMonth(p)
+		}
+	case FrequencyYearly:
+		for m := time.January; m <= time.December; m++ {
+			first := time.Date(p.Year(), m, 1, 0, 0, 0, 0, time.UTC)
+			if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && m != r.DTStart.Month() {
+				continue
+			}
+			if r.matchesMonth(first) {
+				dates = append(dates, r.expandMonth(first)...)
+			}
+		}
+	}
+	return dates
+}
+
+// expandMonth returns the civil dates of the month starting at first that match BYMONTHDAY and BYDAY,
+// or the day of month of DTStart if neither is given.
+func (r RRule) expandMonth(first time.Time) []time.Time {
+	var dates []time.Time
+	last := first.AddDate(0, 1, -1).Day()
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if day := r.DTStart.Day(); day <= last {
+			dates = append(dates, first.AddDate(0, 0, day-1))
+		}
+		return dates
+	}
+	for day := 1; day <= last; day++ {
+		d := first.AddDate(0, 0, day-1)
+		if r.matchesMonthDay(d) && r.matchesWeekday(d) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+func (r RRule) matchesMonth(d time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, d.Month())
+}
+
+func (r RRule) matchesMonthDay(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, md := range r.ByMonthDay {
+		if md == d.Day() || md < 0 && last+md+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RRule) matchesWeekday(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, wd := range r.ByDay {
+		if wd.Weekday != d.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (d.Day()-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (last-d.Day())/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
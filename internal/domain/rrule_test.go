@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	dtstart := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rule      RRule
+		expectErr bool
+	}{
+		{
+			name:      "should validate correct rule",
+			rule:      RRule{Freq: FrequencyWeekly, Interval: 2, DTStart: dtstart, WeekStart: time.Monday},
+			expectErr: false,
+		},
+		{
+			name:      "should validate monthly rule with weekday ordinal",
+			rule:      RRule{Freq: FrequencyMonthly, Interval: 1, DTStart: dtstart, ByDay: []WeekdayNum{{Weekday: time.Monday, N: 1}}},
+			expectErr: false,
+		},
+		{
+			name:      "should return error for unknown frequency",
+			rule:      RRule{Freq: "HOURLY", Interval: 1, DTStart: dtstart},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for zero interval",
+			rule:      RRule{Freq: FrequencyDaily, DTStart: dtstart},
+			expectErr: true,
+		},
+		{
+			name:      "should return error without dtstart",
+			rule:      RRule{Freq: FrequencyDaily, Interval: 1},
+			expectErr: true,
+		},
+		{
+			name:      "should return error when both count and until are set",
+			rule:      RRule{Freq: FrequencyDaily, Interval: 1, DTStart: dtstart, Count: 3, Until: dtstart.AddDate(0, 1, 0)},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for invalid day of month",
+			rule:      RRule{Freq: FrequencyMonthly, Interval: 1, DTStart: dtstart, ByMonthDay: []int{0}},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for weekday ordinal in weekly rule",
+			rule:      RRule{Freq: FrequencyWeekly, Interval: 1, DTStart: dtstart, ByDay: []WeekdayNum{{Weekday: time.Monday, N: 1}}},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for weekday ordinal in yearly rule without bymonth",
+			rule:      RRule{Freq: FrequencyYearly, Interval: 1, DTStart: dtstart, ByDay: []WeekdayNum{{Weekday: time.Monday, N: 1}}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+			}
+		})
+	}
+}
+
+func TestRRule_Next(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name     string
+		rule     RRule
+		time     time.Time
+		expected []time.Time
+	}{
+		{
+			name: "should start at dtstart",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: monday},
+			time: monday.Add(-time.Hour),
+			expected: []time.Time{
+				monday,
+				monday.AddDate(0, 0, 1),
+			},
+		},
+		{
+			name: "should repeat every other week",
+			rule: RRule{Freq: FrequencyWeekly, Interval: 2, DTStart: monday, WeekStart: time.Monday},
+			time: monday,
+			expected: []time.Time{
+				monday.AddDate(0, 0, 14),
+				monday.AddDate(0, 0, 28),
+			},
+		},
+		{
+			name: "should skip ahead to the period of the time",
+			rule: RRule{Freq: FrequencyWeekly, Interval: 2, DTStart: monday, WeekStart: time.Monday},
+			time: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2027, 1, 4, 9, 0, 0, 0, time.UTC),
+				time.Date(2027, 1, 18, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should expand weekdays of a weekly rule",
+			rule: RRule{
+				Freq: FrequencyWeekly, Interval: 2, DTStart: monday, WeekStart: time.Monday,
+				ByDay: []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Friday}},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 23, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should respect the week start of a weekly rule",
+			rule: RRule{
+				Freq: FrequencyWeekly, Interval: 2, DTStart: monday, WeekStart: time.Sunday,
+				ByDay: []WeekdayNum{{Weekday: time.Sunday}, {Weekday: time.Monday}},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 1, 18, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should return the first Monday of every month",
+			rule: RRule{
+				Freq: FrequencyMonthly, Interval: 1, DTStart: monday,
+				ByDay: []WeekdayNum{{Weekday: time.Monday, N: 1}},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 6, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should return the last Friday of every month",
+			rule: RRule{
+				Freq: FrequencyMonthly, Interval: 1, DTStart: monday,
+				ByDay: []WeekdayNum{{Weekday: time.Friday, N: -1}},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should return the last day of every month",
+			rule: RRule{Freq: FrequencyMonthly, Interval: 1, DTStart: monday, ByMonthDay: []int{-1}},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should skip months without the day of dtstart",
+			rule: RRule{Freq: FrequencyMonthly, Interval: 1, DTStart: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)},
+			time: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 5, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should intersect bymonthday and byday",
+			rule: RRule{
+				Freq: FrequencyMonthly, Interval: 1, DTStart: monday,
+				ByDay: []WeekdayNum{{Weekday: time.Friday}}, ByMonthDay: []int{13},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 13, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should repeat yearly on the date of dtstart",
+			rule: RRule{Freq: FrequencyYearly, Interval: 1, DTStart: monday},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2027, 1, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2028, 1, 5, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should expand yearly rules by month and weekday",
+			rule: RRule{
+				Freq: FrequencyYearly, Interval: 1, DTStart: monday,
+				ByMonth: []time.Month{time.May}, ByDay: []WeekdayNum{{Weekday: time.Monday, N: -1}},
+			},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 5, 25, 9, 0, 0, 0, time.UTC),
+				time.Date(2027, 5, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should limit daily rules by month",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: monday, ByMonth: []time.Month{time.March}},
+			time: monday,
+			expected: []time.Time{
+				time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should stop after count occurrences",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: monday, Count: 2},
+			time: monday,
+			expected: []time.Time{
+				monday.AddDate(0, 0, 1),
+				{},
+			},
+		},
+		{
+			name: "should stop after until",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: monday, Until: monday.AddDate(0, 0, 1)},
+			time: monday,
+			expected: []time.Time{
+				monday.AddDate(0, 0, 1),
+				{},
+			},
+		},
+		{
+			name: "should keep the wall clock time across a forward transition",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: time.Date(2026, 3, 7, 9, 0, 0, 0, ny)},
+			time: time.Date(2026, 3, 7, 9, 0, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC), // 09:00 EDT
+				time.Date(2026, 3, 9, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "should keep the wall clock time across a backward transition",
+			rule: RRule{Freq: FrequencyWeekly, Interval: 1, DTStart: time.Date(2026, 10, 26, 9, 0, 0, 0, ny), WeekStart: time.Monday},
+			time: time.Date(2026, 10, 26, 9, 0, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC), // 09:00 EST
+			},
+		},
+		{
+			name: "should move an occurrence skipped by a forward transition to the transition",
+			rule: RRule{Freq: FrequencyDaily, Interval: 1, DTStart: time.Date(2026, 3, 7, 2, 30, 0, 0, ny)},
+			time: time.Date(2026, 3, 7, 2, 30, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), // 03:00 EDT
+				time.Date(2026, 3, 9, 6, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur := tt.time
+			for i, expected := range tt.expected {
+				result := tt.rule.Next(cur)
+				assert.True(t, expected.Equal(result), "occurrence %d: expected %v but got %v", i, expected, result)
+				cur = result
+			}
+		})
+	}
+}
@@ -5,20 +5,25 @@ import (
 	"time"
 )
 
+// WeekdayRule is active between the wall clock times of From and To on the given days of the week.
+// If To is not later than From, the window continues into the next day and
+// belongs to the weekday it starts on.
 type WeekdayRule struct {
 	Op   BlockOps
 	From time.Time // inclusive
 	To   time.Time // exclusive
 	// Weekdays is a list of days of the week when this rule is active.
-	Weekdays []time.Weekday
+	Weekdays   []time.Weekday
+	Exceptions Exceptions
 }
 
-func NewWeekdayRule(ops string, from, to time.Time, weekdays []time.Weekday) (BlockRule, error) {
+func NewWeekdayRule(ops string, from, to time.Time, weekdays []time.Weekday, exceptions Exceptions) (BlockRule, error) {
 	r := WeekdayRule{
-		Op:       BlockOps(ops),
-		From:     from,
-		To:       to,
-		Weekdays: weekdays,
+		Op:         BlockOps(ops),
+		From:       from,
+		To:         to,
+		Weekdays:   weekdays,
+		Exceptions: exceptions,
 	}
 	if err := r.Validate(); err != nil {
 		return nil, fmt.Errorf("failed to create weekday rule: %w", err)
@@ -30,9 +35,6 @@ func (s WeekdayRule) Validate() error {
 	if err := s.Op.Validate(); err != nil {
 		return fmt.Errorf("invalid ops: %w", err)
 	}
-	if s.From.After(s.To) {
-		return fmt.Errorf("from time must be before to time")
-	}
 	if len(s.Weekdays) == 0 {
 		return fmt.Errorf("weekdays cannot be empty")
 	}
@@ -49,18 +51,26 @@ func (s WeekdayRule) Ops() BlockOps {
 }
 
 func (s WeekdayRule) IsActive(t time.Time) bool {
-	from := time.Date(t.Year(), t.Month(), t.Day(), s.From.Hour(), s.From.Minute(), 0, 0, t.Location())
-	to := time.Date(t.Year(), t.Month(), t.Day(), s.To.Hour(), s.To.Minute(), 0, 0, t.Location())
+	return dailyActive(s.From, s.To, t, s.appliesOn)
+}
 
-	w := t.Weekday()
+func (s WeekdayRule) appliesOn(day time.Time) bool {
+	return s.Exceptions.applies(day, s.hasWeekday(day.Weekday()))
+}
+
+func (s WeekdayRule) hasWeekday(w time.Weekday) bool {
 	for _, day := range s.Weekdays {
-		if day != w {
-			continue
-		}
-		if from.After(t) || to.Before(t) {
-			return false
+		if day == w {
+			return true
 		}
-		return true
 	}
 	return false
 }
+
+func (s WeekdayRule) Windows(from, to time.Time) []Window {
+	return dailyWindows(s.From, s.To, from, to, s.appliesOn)
+}
+
+func (s WeekdayRule) NextChange(t time.Time) time.Time {
+	return dailyNextChange(s.From, s.To, t, s.appliesOn)
+}
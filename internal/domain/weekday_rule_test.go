@@ -26,14 +26,14 @@ func TestWeekdayRule_Validate(t *testing.T) {
 			expectErr: false,
 		},
 		{
-			name: "should return error when from time is after to time",
+			name: "should validate overnight rule",
 			rule: WeekdayRule{
 				Op:       BlockOpsBlock,
-				From:     time.Date(0, 1, 1, 23, 0, 0, 0, time.UTC),
-				To:       time.Date(0, 1, 1, 22, 59, 59, 999999999, time.UTC),
-				Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
 			},
-			expectErr: true,
+			expectErr: false,
 		},
 		{
 			name: "should return error when no weekdays are specified",
@@ -111,6 +111,108 @@ func TestWeekdayRule_IsActive(t *testing.T) {
 			time:     time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC), // Wednesday
 			expected: false,
 		},
+		{
+			name: "should return true on the start day of an overnight range",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			time:     time.Date(2025, 1, 3, 23, 0, 0, 0, time.UTC), // Friday
+			expected: true,
+		},
+		{
+			name: "should return true on the morning after the start day of an overnight range",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			time:     time.Date(2025, 1, 4, 5, 0, 0, 0, time.UTC), // Saturday
+			expected: true,
+		},
+		{
+			name: "should return false at exactly the end of an overnight range",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			time:     time.Date(2025, 1, 4, 6, 0, 0, 0, time.UTC), // Saturday
+			expected: false,
+		},
+		{
+			name: "should return false on the morning of the start day of an overnight range",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			time:     time.Date(2025, 1, 3, 5, 0, 0, 0, time.UTC), // Friday
+			expected: false,
+		},
+		{
+			name: "should return false on the evening after the start day of an overnight range",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			time:     time.Date(2025, 1, 4, 23, 0, 0, 0, time.UTC), // Saturday
+			expected: false,
+		},
+		{
+			name: "should attribute the morning after Saturday to Saturday",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Saturday},
+			},
+			time:     time.Date(2025, 1, 5, 1, 0, 0, 0, time.UTC), // Sunday
+			expected: true,
+		},
+		{
+			name: "should return false on skipped dates",
+			rule: WeekdayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Weekdays:   []time.Weekday{time.Wednesday},
+				Exceptions: Exceptions{Skip: NewDateSet(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC), // Wednesday
+			expected: false,
+		},
+		{
+			name: "should return true on forced dates of other weekdays",
+			rule: WeekdayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Weekdays:   []time.Weekday{time.Monday},
+				Exceptions: Exceptions{Force: NewDateSet(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 4, 10, 30, 0, 0, time.UTC), // Saturday
+			expected: true,
+		},
+		{
+			name: "should return false outside the range on forced dates",
+			rule: WeekdayRule{
+				Op:         BlockOpsBlock,
+				From:       time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:         time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Weekdays:   []time.Weekday{time.Monday},
+				Exceptions: Exceptions{Force: NewDateSet(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC))},
+			},
+			time:     time.Date(2025, 1, 4, 19, 30, 0, 0, time.UTC), // Saturday
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,3 +222,133 @@ func TestWeekdayRule_IsActive(t *testing.T) {
 		})
 	}
 }
+
+func TestWeekdayRule_Windows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rule     WeekdayRule
+		from     time.Time
+		to       time.Time
+		expected []Window
+	}{
+		{
+			name: "should return windows on the given weekdays only",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Monday, time.Wednesday},
+			},
+			from: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), // Monday
+			to:   time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 6, 18, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 8, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 8, 18, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "should attribute overnight windows to the start day",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday},
+			},
+			from: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), // Friday
+			to:   time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 3, 22, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 4, 6, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "should apply exceptions",
+			rule: WeekdayRule{
+				Op:       BlockOpsBlock,
+				From:     time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Monday, time.Tuesday},
+				Exceptions: Exceptions{
+					Skip:  NewDateSet(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)),
+					Force: NewDateSet(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)),
+				},
+			},
+			from: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), // Monday
+			to:   time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+			expected: []Window{
+				{Start: time.Date(2025, 1, 7, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 7, 18, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 8, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 8, 18, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Windows(tt.from, tt.to))
+		})
+	}
+}
+
+func TestWeekdayRule_NextChange(t *testing.T) {
+	t.Parallel()
+
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	// 2026-01-05 is a Monday.
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	workdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		name     string
+		rule     WeekdayRule
+		t        time.Time
+		expected time.Time
+	}{
+		{
+			name:     "should return the end of the current window",
+			rule:     WeekdayRule{Op: BlockOpsBlock, From: clock(9), To: clock(17), Weekdays: workdays},
+			t:        at(5, 10),
+			expected: at(5, 17),
+		},
+		{
+			name:     "should return the start of the window on the next weekday",
+			rule:     WeekdayRule{Op: BlockOpsBlock, From: clock(9), To: clock(17), Weekdays: workdays},
+			t:        at(9, 17),
+			expected: at(12, 9),
+		},
+		{
+			name:     "should return the end of an overnight window started on a listed weekday",
+			rule:     WeekdayRule{Op: BlockOpsBlock, From: clock(22), To: clock(6), Weekdays: []time.Weekday{time.Friday}},
+			t:        at(10, 3),
+			expected: at(10, 6),
+		},
+		{
+			name: "should return the start of the window on a forced date",
+			rule: WeekdayRule{
+				Op: BlockOpsBlock, From: clock(9), To: clock(17), Weekdays: workdays,
+				Exceptions: Exceptions{Force: NewDateSet(time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC))},
+			},
+			t:        at(9, 17),
+			expected: at(10, 9),
+		},
+		{
+			name: "should return the end of whole days on consecutive weekdays",
+			rule: WeekdayRule{
+				Op: BlockOpsBlock, From: clock(0), To: clock(0),
+				Weekdays: []time.Weekday{time.Saturday, time.Sunday},
+			},
+			t:        at(10, 12),
+			expected: at(12, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.NextChange(tt.t))
+		})
+	}
+}
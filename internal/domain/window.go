@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Window is the time interval [Start, End).
+type Window struct {
+	Start time.Time // inclusive
+	End   time.Time // exclusive
+}
+
+// contains reports whether t is inside the window.
+func (w Window) contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// clip returns w restricted to [from, to) and whether anything is left.
+func (w Window) clip(from, to time.Time) (Window, bool) {
+	if w.Start.Before(from) {
+		w.Start = from
+	}
+	if w.End.After(to) {
+		w.End = to
+	}
+	return w, w.Start.Before(w.End)
+}
+
+// appendClipped appends w restricted to [from, to) to ws unless it is empty.
+func appendClipped(ws []Window, w Window, from, to time.Time) []Window {
+	if c, ok := w.clip(from, to); ok {
+		ws = append(ws, c)
+	}
+	return ws
+}
+
+// mergeWindows sorts ws and merges overlapping and adjacent windows.
+func mergeWindows(ws []Window) []Window {
+	sorted := slices.Clone(ws)
+	slices.SortFunc(sorted, func(a, b Window) int {
+		return a.Start.Compare(b.Start)
+	})
+	var merged []Window
+	for _, w := range sorted {
+		if n := len(merged); n > 0 && !w.Start.After(merged[n-1].End) {
+			if w.End.After(merged[n-1].End) {
+				merged[n-1].End = w.End
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// subtractWindows removes the windows in b from the merged windows in a.
+func subtractWindows(a, b []Window) []Window {
+	b = mergeWindows(b)
+	var result []Window
+	for _, w := range a {
+		for _, cut := range b {
+			if !cut.End.After(w.Start) || !cut.Start.Before(w.End) {
+				continue
+			}
+			if cut.Start.After(w.Start) {
+				result = append(result, Window{Start: w.Start, End: cut.Start})
+			}
+			w.Start = cut.End
+			if !w.Start.Before(w.End) {
+				break
+			}
+		}
+		if w.Start.Before(w.End) {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// dailyWindow returns the window between the wall clock times of fromClock and toClock
+// (hour, minute and second) started on the calendar date of day in loc.
+//
+// The start is inclusive and the end exclusive. If toClock is not later than fromClock,
+// the window ends on the next day, so equal clocks span a whole day.
+// Both ends are resolved with wallTime: a clock time skipped by a daylight saving time transition
+// resolves to the transition and a repeated clock time to its first occurrence.
+// A window lying entirely inside a skipped hour is therefore empty.
+func dailyWindow(fromClock, toClock, day time.Time, loc *time.Location) Window {
+	endDay := day
+	if clockOf(toClock) <= clockOf(fromClock) {
+		endDay = day.AddDate(0, 0, 1)
+	}
+	return Window{
+		Start: wallTime(day.Year(), day.Month(), day.Day(), fromClock.Hour(), fromClock.Minute(), fromClock.Second(), loc),
+		End:   wallTime(endDay.Year(), endDay.Month(), endDay.Day(), toClock.Hour(), toClock.Minute(), toClock.Second(), loc),
+	}
+}
+
+// clockOf returns the wall clock time of t as the duration since midnight, ignoring fractions of a second.
+func clockOf(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// dailyActive reports whether t is inside the daily window between fromClock and toClock
+// of a day for which include returns true, evaluated in the location of t.
+// A window belongs to the day it starts on, so the window started on the day before t is checked as well.
+func dailyActive(fromClock, toClock, t time.Time, include func(day time.Time) bool) bool {
+	day := civilDate(t)
+	for _, d := range []time.Time{day.AddDate(0, 0, -1), day} {
+		if include != nil && !include(d) {
+			continue
+		}
+		if dailyWindow(fromClock, toClock, d, t.Location()).contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// dailySearchDays is how many days dailyNextChange looks ahead,
+// long enough to find a window of a rule that only applies on a few dates a year.
+const dailySearchDays = 2 * 366
+
+// dailyNextChange returns the earliest time after t at which dailyActive changes,
+// or the zero time if it does not change within dailySearchDays.
+// Windows of consecutive days that touch, such as whole-day windows, are one continuous active period.
+func dailyNextChange(fromClock, toClock, t time.Time, include func(day time.Time) bool) time.Time {
+	day := civilDate(t)
+	last := day.AddDate(0, 0, dailySearchDays-1)
+	var current Window
+	var currentDay time.Time // the last day whose window current includes
+	for d := day.AddDate(0, 0, -1); !d.After(last); d = d.AddDate(0, 0, 1) {
+		if include != nil && !include(d) {
+			continue
+		}
+		w := dailyWindow(fromClock, toClock, d, t.Location())
+		if !w.Start.Before(w.End) {
+			continue
+		}
+		if !current.End.IsZero() && !w.Start.After(current.End) {
+			current.End, currentDay = w.End, d
+			continue
+		}
+		if c := current.changeAfter(t); !c.IsZero() {
+			return c
+		}
+		current, currentDay = w, d
+	}
+	if currentDay.Equal(last) {
+		// The window may continue into days after the search range, so its end is unknown.
+		current.End = time.Time{}
+	}
+	return current.changeAfter(t)
+}
+
+// changeAfter returns the start or end of w, whichever is the first after t, or the zero time if neither is.
+func (w Window) changeAfter(t time.Time) time.Time {
+	if w.Start.After(t) {
+		return w.Start
+	}
+	if w.End.After(t) {
+		return w.End
+	}
+	return time.Time{}
+}
+
+// changeSearchSpans are the increasingly long spans after t in which nextChangeInWindows looks for a change,
+// so that frequent windows are found without computing a year of them.
+var changeSearchSpans = []time.Duration{24 * time.Hour, 8 * 24 * time.Hour, 366 * 24 * time.Hour}
+
+// nextChangeInWindows returns the earliest time after t at which a window returned by windows starts or ends,
+// treating touching windows as one, or the zero time if there is none within the longest search span.
+func nextChangeInWindows(windows func(from, to time.Time) []Window, t time.Time) time.Time {
+	for _, span := range changeSearchSpans {
+		end := t.Add(span)
+		for _, w := range mergeWindows(windows(t, end)) {
+			// Windows are clipped to [t, end), so bounds at t and end are not changes.
+			if w.Start.After(t) {
+				return w.Start
+			}
+			if w.End.Before(end) {
+				return w.End
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// dailyWindows returns the windows between fromClock and toClock on every day overlapping [start, end),
+// for which include returns true, evaluated in the location of start. See dailyWindow for the semantics.
+func dailyWindows(fromClock, toClock, start, end time.Time, include func(day time.Time) bool) []Window {
+	loc := start.Location()
+	var ws []Window
+	// A window started on the day before start may still be active.
+	for d := civilDate(start).AddDate(0, 0, -1); !d.After(civilDate(end.In(loc))); d = d.AddDate(0, 0, 1) {
+		if include != nil && !include(d) {
+			continue
+		}
+		ws = appendClipped(ws, dailyWindow(fromClock, toClock, d, loc), start, end)
+	}
+	return ws
+}
+
+// recurrenceActive reports whether t is inside the window of length d starting at an occurrence of r
+// that is not excluded.
+func recurrenceActive(r Recurrence, d time.Duration, t time.Time, excluded func(time.Time) bool) bool {
+	// Occurrences covering t start in (t - d, t].
+	for s := r.Next(t.Add(-d)); !s.IsZero() && !s.After(t); s = r.Next(s) {
+		if excluded != nil && excluded(s) {
+			continue
+		}
+		if (Window{Start: s, End: s.Add(d)}).contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// recurrenceWindows returns the windows of length d starting at each occurrence of r
+// that overlap [start, end) and are not excluded.
+func recurrenceWindows(r Recurrence, d time.Duration, start, end time.Time, excluded func(time.Time) bool) []Window {
+	var ws []Window
+	for s := r.Next(start.Add(-d)); !s.IsZero() && s.Before(end); s = r.Next(s) {
+		if excluded != nil && excluded(s) {
+			continue
+		}
+		ws = appendClipped(ws, Window{Start: s, End: s.Add(d)}, start, end)
+	}
+	return ws
+}
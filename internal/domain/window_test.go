@@ -0,0 +1,513 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeWindows(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour int) time.Time {
+		return time.Date(2025, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		windows  []Window
+		expected []Window
+	}{
+		{
+			name:     "should return nil for no windows",
+			windows:  nil,
+			expected: nil,
+		},
+		{
+			name:     "should sort disjoint windows",
+			windows:  []Window{{at(5), at(6)}, {at(1), at(2)}},
+			expected: []Window{{at(1), at(2)}, {at(5), at(6)}},
+		},
+		{
+			name:     "should merge overlapping windows",
+			windows:  []Window{{at(1), at(4)}, {at(3), at(6)}},
+			expected: []Window{{at(1), at(6)}},
+		},
+		{
+			name:     "should merge adjacent windows",
+			windows:  []Window{{at(1), at(3)}, {at(3), at(6)}},
+			expected: []Window{{at(1), at(6)}},
+		},
+		{
+			name:     "should merge contained windows",
+			windows:  []Window{{at(1), at(6)}, {at(2), at(3)}},
+			expected: []Window{{at(1), at(6)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeWindows(tt.windows))
+		})
+	}
+}
+
+func TestSubtractWindows(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour int) time.Time {
+		return time.Date(2025, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		a        []Window
+		b        []Window
+		expected []Window
+	}{
+		{
+			name:     "should keep windows without overlap",
+			a:        []Window{{at(1), at(2)}},
+			b:        []Window{{at(3), at(4)}},
+			expected: []Window{{at(1), at(2)}},
+		},
+		{
+			name:     "should split windows",
+			a:        []Window{{at(1), at(6)}},
+			b:        []Window{{at(2), at(3)}, {at(4), at(5)}},
+			expected: []Window{{at(1), at(2)}, {at(3), at(4)}, {at(5), at(6)}},
+		},
+		{
+			name:     "should cut the start and end of windows",
+			a:        []Window{{at(1), at(4)}, {at(5), at(8)}},
+			b:        []Window{{at(3), at(6)}},
+			expected: []Window{{at(1), at(3)}, {at(6), at(8)}},
+		},
+		{
+			name:     "should remove covered windows",
+			a:        []Window{{at(2), at(3)}},
+			b:        []Window{{at(1), at(4)}},
+			expected: nil,
+		},
+		{
+			name:     "should keep adjacent windows",
+			a:        []Window{{at(1), at(2)}},
+			b:        []Window{{at(2), at(3)}},
+			expected: []Window{{at(1), at(2)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, subtractWindows(tt.a, tt.b))
+		})
+	}
+}
+
+func TestWindow_contains(t *testing.T) {
+	t.Parallel()
+
+	w := Window{
+		Start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC),
+	}
+
+	assert.False(t, w.contains(w.Start.Add(-time.Nanosecond)), "expected the window to start at Start")
+	assert.True(t, w.contains(w.Start), "expected the start to be inclusive")
+	assert.True(t, w.contains(w.End.Add(-time.Nanosecond)), "expected the window to last until End")
+	assert.False(t, w.contains(w.End), "expected the end to be exclusive")
+	assert.False(t, Window{Start: w.Start, End: w.Start}.contains(w.Start), "expected an empty window to contain nothing")
+}
+
+func TestDailyWindow(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	clock := func(hour, minute, sec int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, sec, 0, time.UTC)
+	}
+	utc := func(month time.Month, day, hour, minute, sec int) time.Time {
+		return time.Date(2026, month, day, hour, minute, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		day      time.Time
+		loc      *time.Location
+		expected Window
+	}{
+		{
+			name:     "should return the window on the day",
+			from:     clock(9, 0, 0),
+			to:       clock(18, 0, 0),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 9, 0, 0), utc(1, 5, 18, 0, 0)},
+		},
+		{
+			name:     "should keep seconds",
+			from:     clock(9, 0, 30),
+			to:       clock(9, 1, 15),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 9, 0, 30), utc(1, 5, 9, 1, 15)},
+		},
+		{
+			name:     "should ignore fractions of a second",
+			from:     clock(22, 0, 0),
+			to:       time.Date(0, 1, 1, 23, 59, 59, 999999999, time.UTC),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 22, 0, 0), utc(1, 5, 23, 59, 59)},
+		},
+		{
+			name:     "should end on the next day if to is earlier than from",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 22, 0, 0), utc(1, 6, 6, 0, 0)},
+		},
+		{
+			name:     "should end at midnight if to is midnight",
+			from:     clock(22, 0, 0),
+			to:       clock(0, 0, 0),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 22, 0, 0), utc(1, 6, 0, 0, 0)},
+		},
+		{
+			name:     "should span a whole day if from equals to",
+			from:     clock(0, 0, 0),
+			to:       clock(0, 0, 0),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      time.UTC,
+			expected: Window{utc(1, 5, 0, 0, 0), utc(1, 6, 0, 0, 0)},
+		},
+		{
+			name:     "should use the wall clock of the location",
+			from:     clock(9, 0, 0),
+			to:       clock(18, 0, 0),
+			day:      utc(1, 5, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(1, 5, 14, 0, 0), utc(1, 5, 23, 0, 0)},
+		},
+		{
+			// 02:00 EST jumps to 03:00 EDT at 07:00 UTC on 2026-03-08
+			name:     "should start at the transition if from is skipped",
+			from:     clock(2, 30, 0),
+			to:       clock(4, 0, 0),
+			day:      utc(3, 8, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(3, 8, 7, 0, 0), utc(3, 8, 8, 0, 0)},
+		},
+		{
+			name:     "should end at the transition if to is skipped",
+			from:     clock(1, 0, 0),
+			to:       clock(2, 30, 0),
+			day:      utc(3, 8, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(3, 8, 6, 0, 0), utc(3, 8, 7, 0, 0)},
+		},
+		{
+			name:     "should be empty if the window is skipped entirely",
+			from:     clock(2, 15, 0),
+			to:       clock(2, 45, 0),
+			day:      utc(3, 8, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(3, 8, 7, 0, 0), utc(3, 8, 7, 0, 0)},
+		},
+		{
+			name:     "should be an hour shorter overnight into a forward transition",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			day:      utc(3, 7, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(3, 8, 3, 0, 0), utc(3, 8, 10, 0, 0)},
+		},
+		{
+			// 02:00 EDT falls back to 01:00 EST at 06:00 UTC on 2026-11-01
+			name:     "should start at the first occurrence of a repeated from",
+			from:     clock(1, 30, 0),
+			to:       clock(3, 0, 0),
+			day:      utc(11, 1, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(11, 1, 5, 30, 0), utc(11, 1, 8, 0, 0)},
+		},
+		{
+			name:     "should end at the first occurrence of a repeated to",
+			from:     clock(0, 0, 0),
+			to:       clock(1, 30, 0),
+			day:      utc(11, 1, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(11, 1, 4, 0, 0), utc(11, 1, 5, 30, 0)},
+		},
+		{
+			name:     "should be an hour longer overnight into a backward transition",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			day:      utc(10, 31, 0, 0, 0),
+			loc:      ny,
+			expected: Window{utc(11, 1, 2, 0, 0), utc(11, 1, 11, 0, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := dailyWindow(tt.from, tt.to, tt.day, tt.loc)
+			assert.True(t, tt.expected.Start.Equal(w.Start), "expected start %v, got %v", tt.expected.Start, w.Start)
+			assert.True(t, tt.expected.End.Equal(w.End), "expected end %v, got %v", tt.expected.End, w.End)
+		})
+	}
+}
+
+func TestDailyActive(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	clock := func(hour, minute, sec int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, sec, 0, time.UTC)
+	}
+	utc := func(month time.Month, day, hour, minute, sec int) time.Time {
+		return time.Date(2026, month, day, hour, minute, sec, 0, time.UTC)
+	}
+	notOn := func(month time.Month, day int) func(time.Time) bool {
+		return func(d time.Time) bool {
+			return !d.Equal(utc(month, day, 0, 0, 0))
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		include  func(time.Time) bool
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should be active at the start",
+			from:     clock(9, 0, 30),
+			to:       clock(18, 0, 30),
+			time:     utc(1, 5, 9, 0, 30),
+			expected: true,
+		},
+		{
+			name:     "should not be active a nanosecond before the start",
+			from:     clock(9, 0, 30),
+			to:       clock(18, 0, 30),
+			time:     utc(1, 5, 9, 0, 30).Add(-time.Nanosecond),
+			expected: false,
+		},
+		{
+			name:     "should be active a nanosecond before the end",
+			from:     clock(9, 0, 30),
+			to:       clock(18, 0, 30),
+			time:     utc(1, 5, 18, 0, 30).Add(-time.Nanosecond),
+			expected: true,
+		},
+		{
+			name:     "should not be active at the end",
+			from:     clock(9, 0, 30),
+			to:       clock(18, 0, 30),
+			time:     utc(1, 5, 18, 0, 30),
+			expected: false,
+		},
+		{
+			name:     "should be active after midnight in an overnight window",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			time:     utc(1, 6, 5, 59, 59),
+			expected: true,
+		},
+		{
+			name:     "should not be active at the end of an overnight window",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			time:     utc(1, 6, 6, 0, 0),
+			expected: false,
+		},
+		{
+			name:     "should attribute the part after midnight to the start day",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			include:  notOn(1, 5),
+			time:     utc(1, 6, 3, 0, 0),
+			expected: false,
+		},
+		{
+			name:     "should not attribute the part after midnight to the end day",
+			from:     clock(22, 0, 0),
+			to:       clock(6, 0, 0),
+			include:  notOn(1, 6),
+			time:     utc(1, 6, 3, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "should be active all day if from equals to",
+			from:     clock(0, 0, 0),
+			to:       clock(0, 0, 0),
+			time:     utc(1, 5, 23, 59, 59),
+			expected: true,
+		},
+		{
+			name:     "should evaluate in the location of the time",
+			from:     clock(9, 0, 0),
+			to:       clock(18, 0, 0),
+			time:     utc(1, 5, 20, 0, 0).In(ny),
+			expected: true,
+		},
+		{
+			name:     "should be active at a forward transition that skipped the start",
+			from:     clock(2, 30, 0),
+			to:       clock(4, 0, 0),
+			time:     utc(3, 8, 7, 0, 0).In(ny),
+			expected: true,
+		},
+		{
+			name:     "should not be active before a forward transition that skipped the start",
+			from:     clock(2, 30, 0),
+			to:       clock(4, 0, 0),
+			time:     utc(3, 8, 6, 59, 59).In(ny),
+			expected: false,
+		},
+		{
+			name:     "should not be active for a window skipped entirely",
+			from:     clock(2, 15, 0),
+			to:       clock(2, 45, 0),
+			time:     utc(3, 8, 7, 0, 0).In(ny),
+			expected: false,
+		},
+		{
+			name:     "should stay active during the repeated hour after the start",
+			from:     clock(1, 30, 0),
+			to:       clock(3, 0, 0),
+			time:     utc(11, 1, 6, 15, 0).In(ny), // 01:15 EST
+			expected: true,
+		},
+		{
+			name:     "should not be active during the repeated hour after the end",
+			from:     clock(0, 0, 0),
+			to:       clock(1, 30, 0),
+			time:     utc(11, 1, 6, 45, 0).In(ny), // 01:45 EST
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, dailyActive(tt.from, tt.to, tt.time, tt.include))
+		})
+	}
+}
+
+func TestDailyActive_MatchesDailyWindows(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	rules := [][2]time.Time{
+		{clock(9, 0), clock(18, 0)},
+		{clock(22, 0), clock(6, 0)},
+		{clock(1, 30), clock(2, 30)},
+		{clock(2, 15), clock(2, 45)},
+		{clock(0, 0), clock(0, 0)},
+	}
+	// Covers both transitions of 2026 in New York.
+	for _, start := range []time.Time{
+		time.Date(2026, 3, 7, 0, 0, 0, 0, ny),
+		time.Date(2026, 10, 31, 0, 0, 0, 0, ny),
+	} {
+		end := start.AddDate(0, 0, 3)
+		for _, r := range rules {
+			ws := dailyWindows(r[0], r[1], start, end, nil)
+			for at := start; at.Before(end); at = at.Add(15 * time.Minute) {
+				inWindows := false
+				for _, w := range ws {
+					inWindows = inWindows || w.contains(at)
+				}
+				assert.Equal(t, inWindows, dailyActive(r[0], r[1], at, nil),
+					"rule %s-%s at %v", r[0].Format("15:04"), r[1].Format("15:04"), at)
+			}
+		}
+	}
+}
+
+func TestRecurrenceActive(t *testing.T) {
+	t.Parallel()
+
+	r := dailyCron(9, 0)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	skipSecond := func(s time.Time) bool {
+		return s.Equal(at(2, 9, 0))
+	}
+
+	assert.True(t, recurrenceActive(r, time.Hour, at(1, 9, 0), nil), "expected the start to be inclusive")
+	assert.True(t, recurrenceActive(r, time.Hour, at(1, 10, 0).Add(-time.Nanosecond), nil))
+	assert.False(t, recurrenceActive(r, time.Hour, at(1, 10, 0), nil), "expected the end to be exclusive")
+	assert.False(t, recurrenceActive(r, time.Hour, at(1, 9, 0).Add(-time.Nanosecond), nil))
+	assert.False(t, recurrenceActive(r, time.Hour, at(2, 9, 30), skipSecond), "expected excluded occurrences to be skipped")
+	assert.True(t, recurrenceActive(r, 25*time.Hour, at(2, 9, 30), skipSecond), "expected overlapping occurrences to be considered")
+}
+
+func TestDailyNextChange(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	clock := func(hour, min int) time.Time {
+		return time.Date(0, 1, 1, hour, min, 0, 0, time.UTC)
+	}
+
+	// 2026-03-08 02:00 does not exist in New York; the window starts at the transition.
+	assert.Equal(t,
+		time.Date(2026, 3, 8, 3, 0, 0, 0, ny),
+		dailyNextChange(clock(2, 30), clock(4, 0), time.Date(2026, 3, 8, 1, 0, 0, 0, ny), nil),
+	)
+	// Windows of a skipped day are not active, even if the previous one continues into it.
+	skipSunday := func(day time.Time) bool { return day.Weekday() != time.Sunday }
+	assert.Equal(t,
+		time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC),
+		dailyNextChange(clock(0, 0), clock(0, 0), time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC), skipSunday),
+	)
+	assert.True(t, dailyNextChange(clock(9, 0), clock(17, 0), time.Now(), func(time.Time) bool { return false }).IsZero(),
+		"expected no change for a rule that never applies")
+}
+
+func TestDailyNextChange_MatchesDailyActive(t *testing.T) {
+	t.Parallel()
+
+	ny := mustLoadLocation(t, "America/New_York")
+	clock := func(hour, min int) time.Time {
+		return time.Date(0, 1, 1, hour, min, 0, 0, time.UTC)
+	}
+	weekdays := func(day time.Time) bool { return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday }
+	rules := []struct{ from, to time.Time }{
+		{clock(9, 0), clock(17, 0)},
+		{clock(22, 0), clock(6, 0)},
+		{clock(1, 30), clock(2, 30)},
+		{clock(0, 0), clock(0, 0)},
+	}
+
+	// Step through the days around the DST transitions and check that the state changes exactly at the returned times.
+	start := time.Date(2026, 3, 6, 0, 0, 0, 0, ny)
+	end := time.Date(2026, 3, 11, 0, 0, 0, 0, ny)
+	for _, r := range rules {
+		for tm := start; tm.Before(end); tm = tm.Add(30 * time.Minute) {
+			c := dailyNextChange(r.from, r.to, tm, weekdays)
+			active := dailyActive(r.from, r.to, tm, weekdays)
+			for s := tm.Add(30 * time.Minute); s.Before(c); s = s.Add(30 * time.Minute) {
+				assert.Equal(t, active, dailyActive(r.from, r.to, s, weekdays), "unexpected change before %v from %v", c, tm)
+			}
+			if !c.IsZero() {
+				assert.NotEqual(t, active, dailyActive(r.from, r.to, c, weekdays), "expected a change at %v from %v", c, tm)
+			}
+		}
+	}
+}
@@ -2,21 +2,53 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/alkshmir/sinkhole-detox/internal/domain"
 )
 
 type BlockerFactory struct {
+	// DateLists are the date lists that blockers and rules can reference by name.
+	DateLists map[string]DateList
+	// DomainGroups are the lists of domains that blockers can reference by name.
+	DomainGroups map[string][]string
+	// Schedules are the lists of rules that blockers and rules can reference by name.
+	Schedules map[string][]Rule
+	// Timezone is the IANA name of the time zone in which rules are evaluated,
+	// unless a blocker sets its own. The time of the request is used as is if empty.
+	Timezone string
 }
 
+// GenBlockers converts the blockers of a configuration. It reports every problem it finds, joined with errors.Join,
+// each naming the blocker and, for problems of rules, the rule index; Problems splits them.
+// Problems of the definitions do not stop blockers from being checked.
 func (f *BlockerFactory) GenBlockers(ctx context.Context, configs []Blocker) ([]domain.Blocker, error) {
+	defs, err := newDefinitions(f.DateLists, f.DomainGroups, f.Schedules)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if f.Timezone != "" {
+		location, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unknown timezone %s: %w", f.Timezone, err))
+		}
+		defs.location = location
+	}
+
 	var blockers []domain.Blocker
-	for _, config := range configs {
-		blocker, err := config.ToBlocker(ctx)
+	for i, config := range configs {
+		bs, err := config.toBlockers(ctx, defs)
 		if err != nil {
-			return nil, err
+			errs = append(errs, wrapProblems(err, "invalid blocker %s", config.displayName(i)))
+			continue
 		}
-		blockers = append(blockers, blocker)
+		blockers = append(blockers, bs...)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
 	}
 	return blockers, nil
 }
@@ -127,3 +127,290 @@ func TestBlockerFactory_GenBlockers(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockerFactory_GenBlockers_DateLists(t *testing.T) {
+	t.Parallel()
+
+	workHours := Rule{
+		Type:     "weekday",
+		Ops:      "block",
+		Start:    "09:00",
+		End:      "18:00",
+		Weekdays: []int{1, 2, 3, 4, 5},
+	}
+	factory := BlockerFactory{
+		DateLists: map[string]DateList{
+			"holidays": {Dates: []string{"2026-01-01"}},
+			"makeup":   {Dates: []string{"2026-01-03"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		configs     []Blocker
+		expected    domain.Exceptions
+		expectError bool
+	}{
+		{
+			name: "should apply blocker exceptions to rules",
+			configs: []Blocker{
+				{Name: "twitter", Domain: "twitter.com", SkipDates: []string{"holidays"}, Rules: []Rule{workHours}},
+			},
+			expected: domain.Exceptions{Skip: domain.NewDateSet(date(2026, 1, 1))},
+		},
+		{
+			name: "should combine blocker and rule exceptions",
+			configs: []Blocker{
+				{
+					Name:      "twitter",
+					Domain:    "twitter.com",
+					SkipDates: []string{"holidays"},
+					Rules: []Rule{
+						{
+							Type:       workHours.Type,
+							Ops:        workHours.Ops,
+							Start:      workHours.Start,
+							End:        workHours.End,
+							Weekdays:   workHours.Weekdays,
+							ForceDates: []string{"makeup"},
+						},
+					},
+				},
+			},
+			expected: domain.Exceptions{
+				Skip:  domain.NewDateSet(date(2026, 1, 1)),
+				Force: domain.NewDateSet(date(2026, 1, 3)),
+			},
+		},
+		{
+			name: "should return error for unknown date lists",
+			configs: []Blocker{
+				{Name: "twitter", Domain: "twitter.com", SkipDates: []string{"unknown"}, Rules: []Rule{workHours}},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for exceptions on unsupported rule types",
+			configs: []Blocker{
+				{
+					Name:   "twitter",
+					Domain: "twitter.com",
+					Rules: []Rule{
+						{Type: "cron", Ops: "block", Cron: "0 9 * * *", Duration: "1h", SkipDates: []string{"holidays"}},
+					},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockers, err := factory.GenBlockers(context.Background(), tt.configs)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, blockers[0].Rules[0].(domain.WeekdayRule).Exceptions)
+		})
+	}
+}
+
+func TestBlockerFactory_GenBlockers_Timezone(t *testing.T) {
+	t.Parallel()
+
+	configs := []Blocker{
+		{Name: "twitter", Domain: "twitter.com"},
+		{Name: "x", Domain: "x.com", Timezone: "America/New_York"},
+	}
+
+	factory := BlockerFactory{Timezone: "Asia/Tokyo"}
+	blockers, err := factory.GenBlockers(context.Background(), configs)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", blockers[0].Location.String(), "expected the global timezone")
+	assert.Equal(t, "America/New_York", blockers[1].Location.String(), "expected the blocker timezone to take precedence")
+
+	factory = BlockerFactory{}
+	blockers, err = factory.GenBlockers(context.Background(), configs[:1])
+	assert.NoError(t, err)
+	assert.Nil(t, blockers[0].Location, "expected no location without timezone")
+
+	factory = BlockerFactory{Timezone: "Mars/Olympus"}
+	_, err = factory.GenBlockers(context.Background(), configs)
+	assert.Error(t, err, "expected error for unknown timezone")
+}
+
+func TestBlockerFactory_GenBlockers_DomainGroups(t *testing.T) {
+	t.Parallel()
+
+	factory := BlockerFactory{
+		DomainGroups: map[string][]string{
+			"social": {"twitter.com", "*.x.com"},
+		},
+	}
+	configs := []Blocker{
+		{
+			Name:         "distractions",
+			Domain:       "x.com",
+			Domains:      []string{"reddit.com"},
+			DomainGroups: []string{"Social"},
+			Subdomains:   []string{"mobile"},
+			Rules: []Rule{
+				{Type: "everyday", Ops: "block", Start: "22:00", End: "06:00"},
+			},
+		},
+	}
+
+	blockers, err := factory.GenBlockers(context.Background(), configs)
+	assert.NoError(t, err)
+
+	rules := []domain.BlockRule{
+		domain.EveryDayRule{
+			Op:   domain.BlockOpsBlock,
+			From: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+			To:   time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+		},
+	}
+	assert.Equal(t, []domain.Blocker{
+		{
+			Domain:            "x.com",
+			IncludeSubdomains: true, // from the group
+			Subdomains:        []string{"mobile.x.com"},
+			ForwardTo:         net.IPv4(0, 0, 0, 0),
+			Rules:             rules,
+		},
+		{
+			Domain:     "reddit.com",
+			Subdomains: []string{"mobile.reddit.com"},
+			ForwardTo:  net.IPv4(0, 0, 0, 0),
+			Rules:      rules,
+		},
+		{
+			Domain:     "twitter.com",
+			Subdomains: []string{"mobile.twitter.com"},
+			ForwardTo:  net.IPv4(0, 0, 0, 0),
+			Rules:      rules,
+		},
+	}, blockers)
+
+	configs[0].DomainGroups = []string{"news"}
+	_, err = factory.GenBlockers(context.Background(), configs)
+	assert.ErrorContains(t, err, "distractions", "expected the error to name the blocker")
+	assert.ErrorContains(t, err, "unknown domain group: news")
+}
+
+func TestBlockerFactory_GenBlockers_Schedules(t *testing.T) {
+	t.Parallel()
+
+	factory := BlockerFactory{
+		Schedules: map[string][]Rule{
+			"work_hours": {
+				{Type: "weekday", Ops: "block", Start: "09:00", End: "18:00", Weekdays: []int{1, 2, 3, 4, 5}},
+			},
+		},
+	}
+	configs := []Blocker{
+		{
+			Name:      "twitter",
+			Domain:    "twitter.com",
+			Schedules: []string{"work_hours"},
+			Rules: []Rule{
+				{Type: "everyday", Ops: "allow", Start: "12:00", End: "13:00"},
+			},
+		},
+	}
+
+	blockers, err := factory.GenBlockers(context.Background(), configs)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.BlockRule{
+		domain.WeekdayRule{
+			Op:       domain.BlockOpsBlock,
+			From:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		},
+		domain.EveryDayRule{
+			Op:   domain.BlockOpsAllow,
+			From: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+			To:   time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
+	}, blockers[0].Rules)
+
+	configs = append(configs, Blocker{
+		Name:      "x",
+		Domain:    "x.com",
+		Schedules: []string{"work-hours"},
+	})
+	_, err = factory.GenBlockers(context.Background(), configs)
+	assert.ErrorContains(t, err, "invalid blocker x: unknown schedule: work-hours")
+}
+
+func TestBlockerFactory_GenBlockers_Problems(t *testing.T) {
+	t.Parallel()
+
+	factory := BlockerFactory{}
+	configs := []Blocker{
+		{
+			Name:        "twitter",
+			Domain:      "twitter.com",
+			ForwardToV6: "0.0.0.0",
+			Rules: []Rule{
+				{Type: "everyday", Ops: "block", Start: "09:00", End: "18:00"},
+				{Type: "everyday", Ops: "block", Start: "25:00", End: "18:00"},
+				{Type: "weekly", Ops: "block"},
+			},
+		},
+		{
+			Domain: "-invalid.com",
+			Rules: []Rule{
+				{Type: "everyday", Ops: "deny", Start: "09:00", End: "18:00"},
+			},
+		},
+		{
+			Name:   "valid",
+			Domain: "example.com",
+		},
+	}
+
+	_, err := factory.GenBlockers(context.Background(), configs)
+	problems := Problems(err)
+	assert.Len(t, problems, 5, "expected every problem to be reported: %v", err)
+	assert.ErrorContains(t, problems[0], "invalid blocker twitter: forward_to_v6 must be an IPv6 address")
+	assert.ErrorContains(t, problems[1], "invalid blocker twitter: rule 1: failed to parse start time 25:00")
+	assert.ErrorContains(t, problems[2], "invalid blocker twitter: rule 2: unknown rule type: weekly")
+	assert.ErrorContains(t, problems[3], "invalid blocker #1: rule 0: failed to create everyday rule")
+	assert.ErrorContains(t, problems[4], `invalid blocker #1: invalid domain "-invalid.com"`)
+
+	factory = BlockerFactory{
+		DateLists: map[string]DateList{
+			"a": {Dates: []string{"tomorrow", "2026-01-01"}},
+			"b": {Dates: []string{"2026/01/01"}},
+		},
+		Timezone: "Mars/Olympus_Mons",
+	}
+	configs = []Blocker{
+		{
+			Name:         "news",
+			Domain:       "news.example.com",
+			DomainGroups: []string{"nope1", "nope2"},
+			SkipDates:    []string{"a", "x", "y"},
+			Schedules:    []string{"missing"},
+			Rules: []Rule{
+				{Type: "everyday", Ops: "block", Start: "25:00", End: "18:00"},
+			},
+		},
+	}
+	_, err = factory.GenBlockers(context.Background(), configs)
+	problems = Problems(err)
+	assert.Len(t, problems, 9, "expected problems of definitions not to hide those of blockers: %v", err)
+	assert.ErrorContains(t, problems[0], "invalid date list a: failed to parse date tomorrow")
+	assert.ErrorContains(t, problems[1], "invalid date list b: failed to parse date 2026/01/01")
+	assert.ErrorContains(t, problems[2], "unknown timezone Mars/Olympus_Mons")
+	assert.ErrorContains(t, problems[3], "invalid blocker news: unknown domain group: nope1")
+	assert.ErrorContains(t, problems[4], "invalid blocker news: unknown domain group: nope2")
+	assert.ErrorContains(t, problems[5], "invalid blocker news: unknown date list: x")
+	assert.ErrorContains(t, problems[6], "invalid blocker news: unknown date list: y")
+	assert.ErrorContains(t, problems[7], "invalid blocker news: unknown schedule: missing")
+	assert.ErrorContains(t, problems[8], "invalid blocker news: rule 0: failed to parse start time 25:00")
+}
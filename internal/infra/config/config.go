@@ -2,121 +2,457 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig `mapstructure:"server"`
-	Blockers []Blocker    `mapstructure:"blockers"`
+	Server       ServerConfig        `mapstructure:"server"`
+	Timezone     string              `mapstructure:"timezone"` // IANA time zone name, defaults to the local time zone
+	DateLists    map[string]DateList `mapstructure:"date_lists"`
+	DomainGroups map[string][]string `mapstructure:"domain_groups"` // named lists of domains that blockers can reference
+	Schedules    map[string][]Rule   `mapstructure:"schedules"`     // named lists of rules that blockers and rules can reference
+	Blockers     []Blocker           `mapstructure:"blockers"`
 }
 
 type ServerConfig struct {
-	Port int `mapstructure:"port"`
+	Port    int    `mapstructure:"port"`
+	RPZFile string `mapstructure:"rpz_file"` // path to keep the RPZ zone written to, updated at every schedule change
+}
+
+// DateList is a named list of dates, e.g. public holidays, that blockers and rules can skip or force.
+type DateList struct {
+	Dates []string `mapstructure:"dates"` // YYYY-MM-DD
+	File  string   `mapstructure:"file"`  // path to a file with a YYYY-MM-DD date at the start of each line
 }
 
 type Blocker struct {
-	Name      string `mapstructure:"name"`
-	Domain    string `mapstructure:"domain"`
-	ForwardTo string `mapstructure:"forward_to"` // IP address to forward the request to this domain
-	Rules     []Rule `mapstructure:"rules"`
+	Name              string   `mapstructure:"name"`
+	Domain            string   `mapstructure:"domain"`             // domain name, "*.example.com" includes all subdomains
+	Domains           []string `mapstructure:"domains"`            // further domains sharing the rules
+	DomainGroups      []string `mapstructure:"domain_groups"`      // names of domain groups whose domains share the rules
+	IncludeSubdomains bool     `mapstructure:"include_subdomains"` // block all subdomains of the domain as well
+	Subdomains        []string `mapstructure:"subdomains"`         // known subdomains such as "mobile", listed by formats without wildcards
+	ForwardTo         string   `mapstructure:"forward_to"`         // IP address to forward the request to this domain
+	ForwardToV6       string   `mapstructure:"forward_to_v6"`      // optional IPv6 address to forward the request to this domain, such as "::"
+	Timezone          string   `mapstructure:"timezone"`           // IANA time zone name, overrides the global timezone
+	SkipDates         []string `mapstructure:"skip_dates"`         // names of date lists on which everyday and weekday rules do not apply
+	ForceDates        []string `mapstructure:"force_dates"`        // names of date lists on which everyday and weekday rules always apply
+	Schedules         []string `mapstructure:"schedules"`          // names of schedules whose rules precede the inline rules
+	Rules             []Rule   `mapstructure:"rules"`
 }
 
 type Rule struct {
-	Type     string `mapstructure:"type"`     // "everyday" / "weekday"
+	Type     string `mapstructure:"type"`     // "everyday" / "weekday" / "daterange" / "cron" / "rrule" / "ical" / "schedule"
 	Ops      string `mapstructure:"ops"`      // "block" / "allow"
-	Start    string `mapstructure:"start"`    // HH:MM
-	End      string `mapstructure:"end"`      // HH:MM
+	Start    string `mapstructure:"start"`    // HH:MM[:SS], inclusive
+	End      string `mapstructure:"end"`      // HH:MM[:SS], exclusive, not later than Start for windows crossing midnight
 	Weekdays []int  `mapstructure:"weekdays"` // 0=Sunday, 1=Monday, ..., 6=Saturday
+	StartAt  string `mapstructure:"start_at"` // RFC 3339 or YYYY-MM-DD, used by "daterange" and as DTSTART of "rrule"
+	EndAt    string `mapstructure:"end_at"`   // RFC 3339 or YYYY-MM-DD, used by "daterange"
+	Cron     string `mapstructure:"cron"`     // cron expression, used by "cron"
+	RRule    string `mapstructure:"rrule"`    // iCalendar RRULE, used by "rrule"
+	Duration string `mapstructure:"duration"` // Go duration such as "3h", used by "cron" and "rrule"
+	File     string `mapstructure:"file"`     // path to an iCalendar file, used by "ical"
+	Schedule string `mapstructure:"schedule"` // name of a schedule whose rules replace this rule, used by "schedule"
+	// SkipDates and ForceDates are names of date lists, used by "everyday" and "weekday".
+	SkipDates  []string `mapstructure:"skip_dates"`
+	ForceDates []string `mapstructure:"force_dates"`
 }
 
+// LoadConfig reads the configuration file at path and checks its time zones. It can be called again to reload the file.
 func LoadConfig(path string) (*Config, error) {
+	cfg, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.validateTimezones(); err != nil {
+		return nil, err
+	}
+	slog.Info("Configuration loaded successfully", "config", cfg)
+
+	return cfg, nil
+}
 
-	viper.SetConfigFile(path)
+// ReadConfig reads the configuration file at path without checking it, so that BlockerFactory.GenBlockers
+// can report every problem of the configuration, including those LoadConfig checks.
+func ReadConfig(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
 
 	slog.Info("Loading configuration from file", "path", path)
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
-	slog.Info("Configuration loaded successfully", "config", cfg)
-
 	return &cfg, nil
 }
 
+// WatchConfig calls onChange with the reloaded configuration, or the error reloading it,
+// whenever the file at path is written or replaced. It watches until the process exits.
+func WatchConfig(path string, onChange func(*Config, error)) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.OnConfigChange(func(e fsnotify.Event) {
+		slog.Info("Configuration file changed", "path", path, "event", e.Op.String())
+		onChange(LoadConfig(path))
+	})
+	v.WatchConfig()
+}
+
+// validateTimezones checks that all configured time zones are known.
+func (c *Config) validateTimezones() error {
+	var errs []error
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("unknown timezone %s: %w", c.Timezone, err))
+	}
+	for i, b := range c.Blockers {
+		if _, err := time.LoadLocation(b.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("unknown timezone %s of blocker %s: %w", b.Timezone, b.displayName(i), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// definitions are the top-level sections of the configuration that blockers and rules reference by name.
+type definitions struct {
+	dateLists    map[string]domain.DateSet
+	domainGroups map[string][]string
+	schedules    map[string][]Rule
+	// location is the global time zone, nil for the local time zone of the process.
+	location *time.Location
+}
+
+// newDefinitions resolves the top-level definitions. Names are case-insensitive.
+// Every problem is reported along with the definitions, so that blockers can still be checked against them.
+// Date lists keep their valid dates, so that references to them are not reported as unknown.
+func newDefinitions(dateLists map[string]DateList, domainGroups map[string][]string, schedules map[string][]Rule) (definitions, error) {
+	defs := definitions{
+		dateLists:    map[string]domain.DateSet{},
+		domainGroups: map[string][]string{},
+		schedules:    map[string][]Rule{},
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(dateLists)) {
+		set, err := dateLists[name].toDateSet()
+		if err != nil {
+			errs = append(errs, wrapProblems(err, "invalid date list %s", name))
+		}
+		defs.dateLists[strings.ToLower(name)] = set
+	}
+	for name, domains := range domainGroups {
+		defs.domainGroups[strings.ToLower(name)] = domains
+	}
+	for name, rules := range schedules {
+		defs.schedules[strings.ToLower(name)] = rules
+	}
+	if err := defs.validateSchedules(); err != nil {
+		errs = append(errs, err)
+	}
+	return defs, errors.Join(errs...)
+}
+
+// ToBlocker converts a blocker with a single domain on its own. References to top-level definitions
+// cannot be resolved; use BlockerFactory to convert blockers of a complete configuration.
 func (b *Blocker) ToBlocker(ctx context.Context) (domain.Blocker, error) {
-	forwardTo := net.ParseIP(b.ForwardTo)
-	if forwardTo == nil {
-		slog.Info("ForwardTo IP is invalid or not set, defaulting to 0.0.0.0", "forwardTo", b.ForwardTo)
-		forwardTo = net.IPv4(0, 0, 0, 0) // Defaul
+	blockers, err := b.toBlockers(ctx, definitions{})
+	if err != nil {
+		return domain.Blocker{}, err
+	}
+	if len(blockers) != 1 {
+		return domain.Blocker{}, fmt.Errorf("blocker has %d domains, use BlockerFactory to convert it", len(blockers))
 	}
+	return blockers[0], nil
+}
 
-	rules := make([]domain.BlockRule, len(b.Rules))
-	for i, r := range b.Rules {
-		start, err := parseTime(r.Start)
+// displayName returns the name of the blocker at index i of the configuration for messages.
+func (b *Blocker) displayName(i int) string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// toBlockers converts the blocker into a blocker for each of its domains, all sharing the same rules.
+// It reports every problem of the blocker, with the index of the rule for problems of rules.
+func (b *Blocker) toBlockers(ctx context.Context, defs definitions) ([]domain.Blocker, error) {
+	var errs []error
+	names, err := defs.domains(b.Domain, b.Domains, b.DomainGroups)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	forwardTo := net.IPv4(0, 0, 0, 0) // Default
+	if b.ForwardTo == "" {
+		slog.Info("ForwardTo IP is not set, defaulting to 0.0.0.0")
+	} else if forwardTo = net.ParseIP(b.ForwardTo); forwardTo == nil {
+		errs = append(errs, fmt.Errorf("forward_to must be an IP address: %s", b.ForwardTo))
+	}
+	var forwardToV6 net.IP
+	if b.ForwardToV6 != "" {
+		forwardToV6 = net.ParseIP(b.ForwardToV6)
+		if forwardToV6 == nil || forwardToV6.To4() != nil {
+			errs = append(errs, fmt.Errorf("forward_to_v6 must be an IPv6 address: %s", b.ForwardToV6))
+		}
+	}
+
+	exceptions, err := defs.exceptions(b.SkipDates, b.ForceDates)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	location := defs.location
+	if b.Timezone != "" {
+		location, err = time.LoadLocation(b.Timezone)
 		if err != nil {
-			return domain.Blocker{}, fmt.Errorf("failed to parse start time %s: %w", r.Start, err)
+			errs = append(errs, fmt.Errorf("unknown timezone %s: %w", b.Timezone, err))
 		}
-		end, err := parseTime(r.End)
+	}
+	loc := location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	configRules, err := defs.rules(b.Schedules, b.Rules)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// Rules are numbered like in the status, with the rules of referenced schedules expanded in place.
+	rules := make([]domain.BlockRule, len(configRules))
+	for i, r := range configRules {
+		rule, err := r.toBlockRule(defs, exceptions, loc)
 		if err != nil {
-			return domain.Blocker{}, fmt.Errorf("failed to parse end time %s: %w", r.End, err)
+			errs = append(errs, wrapProblems(err, "rule %d", i))
+			continue
 		}
+		rules[i] = rule
+	}
 
-		var rule domain.BlockRule
-		// TODO: rewrite to abstract factory pattern
-		switch r.Type {
-		case "everyday":
-			rule, err = domain.NewEveryDayRule(
-				r.Ops,
-				start,
-				end,
-			)
+	var blockers []domain.Blocker
+	index := map[string]int{}
+	for _, n := range names {
+		name, wildcard, err := parseDomain(n)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if i, ok := index[name]; ok {
+			blockers[i].IncludeSubdomains = blockers[i].IncludeSubdomains || wildcard
+			continue
+		}
+		var subdomains []string
+		for _, label := range b.Subdomains {
+			subdomain, err := parseSubdomain(label, name)
 			if err != nil {
-				return domain.Blocker{}, fmt.Errorf("failed to create everyday rule: %w", err)
+				errs = append(errs, err)
+				continue
 			}
+			subdomains = append(subdomains, subdomain)
+		}
+		index[name] = len(blockers)
+		blockers = append(blockers, domain.Blocker{
+			Domain:            name,
+			IncludeSubdomains: wildcard || b.IncludeSubdomains,
+			Subdomains:        subdomains,
+			ForwardTo:         forwardTo,
+			ForwardToV6:       forwardToV6,
+			Rules:             rules,
+			Location:          location,
+		})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return blockers, nil
+}
 
-		case "weekday":
-			weekdays, err := parseWeekdays(r.Weekdays)
-			if err != nil {
-				return domain.Blocker{}, fmt.Errorf("failed to parse weekdays: %w", err)
-			}
-			rule, err = domain.NewWeekdayRule(
-				r.Ops,
-				start,
-				end,
-				weekdays,
-			)
-			if err != nil {
-				return domain.Blocker{}, fmt.Errorf("failed to create weekday rule: %w", err)
-			}
-		default:
-			return domain.Blocker{}, fmt.Errorf("unknown rule type: %s", r.Type)
+// toBlockRule converts the rule. Exceptions of the blocker are applied to everyday and weekday rules,
+// and timestamps without offset are interpreted in loc.
+func (r *Rule) toBlockRule(defs definitions, blockerExceptions domain.Exceptions, loc *time.Location) (domain.BlockRule, error) {
+	if r.Type != "everyday" && r.Type != "weekday" && (len(r.SkipDates) > 0 || len(r.ForceDates) > 0) {
+		return nil, fmt.Errorf("skip_dates and force_dates are not supported by %s rules", r.Type)
+	}
+	exceptions, err := defs.exceptions(r.SkipDates, r.ForceDates)
+	if err != nil {
+		return nil, err
+	}
+	exceptions = domain.Exceptions{
+		Skip:  blockerExceptions.Skip.Union(exceptions.Skip),
+		Force: blockerExceptions.Force.Union(exceptions.Force),
+	}
+
+	var rule domain.BlockRule
+	// TODO: rewrite to abstract factory pattern
+	switch r.Type {
+	case "everyday":
+		start, end, err := r.parseWindow()
+		if err != nil {
+			return nil, err
 		}
-		rules[i] = rule
+		rule, err = domain.NewEveryDayRule(
+			r.Ops,
+			start,
+			end,
+			exceptions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create everyday rule: %w", err)
+		}
+
+	case "weekday":
+		start, end, err := r.parseWindow()
+		if err != nil {
+			return nil, err
+		}
+		weekdays, err := parseWeekdays(r.Weekdays)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse weekdays: %w", err)
+		}
+		rule, err = domain.NewWeekdayRule(
+			r.Ops,
+			start,
+			end,
+			weekdays,
+			exceptions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create weekday rule: %w", err)
+		}
+
+	case "daterange":
+		rule, err = r.toDateRangeRule(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create daterange rule: %w", err)
+		}
+	case "cron", "rrule":
+		rule, err = r.toRecurrenceRule(loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s rule: %w", r.Type, err)
+		}
+	case "ical":
+		calendar, err := NewICSCalendar(r.File, loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load calendar %s: %w", r.File, err)
+		}
+		rule, err = domain.NewEventRule(r.Ops, calendar)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ical rule: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule type: %s", r.Type)
 	}
+	return rule, nil
+}
 
-	return domain.Blocker{
-		Domain:    b.Domain,
-		ForwardTo: forwardTo,
-		Rules:     rules,
-	}, nil
+func (r *Rule) parseWindow() (time.Time, time.Time, error) {
+	start, err := parseTime(r.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start time %s: %w", r.Start, err)
+	}
+	end, err := parseTime(r.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end time %s: %w", r.End, err)
+	}
+	return start, end, nil
 }
 
-func parseTime(s string) (time.Time, error) {
-	t, err := time.Parse("15:04", s)
+func (r *Rule) toDateRangeRule(loc *time.Location) (domain.BlockRule, error) {
+	startAt, err := parseTimestamp(r.StartAt, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse start_at %s: %w", r.StartAt, err)
+	}
+	endAt, err := parseTimestamp(r.EndAt, loc)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse time %s: %w", s, err)
+		return nil, fmt.Errorf("failed to parse end_at %s: %w", r.EndAt, err)
+	}
+	if r.Start == "" && r.End == "" {
+		return domain.NewDateRangeRule(r.Ops, startAt, endAt)
+	}
+	start, end, err := r.parseWindow()
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewDailyDateRangeRule(r.Ops, startAt, endAt, start, end)
+}
+
+func (r *Rule) toRecurrenceRule(loc *time.Location) (domain.BlockRule, error) {
+	duration, err := time.ParseDuration(r.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration %s: %w", r.Duration, err)
+	}
+
+	var recurrence domain.Recurrence
+	if r.Type == "cron" {
+		recurrence, err = parseCron(r.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cron expression %s: %w", r.Cron, err)
+		}
+	} else {
+		dtstart, err := parseTimestamp(r.StartAt, loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start_at %s: %w", r.StartAt, err)
+		}
+		recurrence, err = parseRRule(r.RRule, dtstart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rrule %s: %w", r.RRule, err)
+		}
+	}
+	return domain.NewRecurrenceRule(r.Ops, recurrence, duration)
+}
+
+// timestampLayouts are accepted by parseTimestamp in addition to RFC 3339.
+// They have no offset and are interpreted in the time zone of the blocker.
+var timestampLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, or a date and optional time without offset in loc.
+func parseTimestamp(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected RFC 3339 timestamp, YYYY-MM-DDTHH:MM[:SS] or YYYY-MM-DD")
+}
+
+// timeLayouts are the accepted layouts of a wall clock time.
+var timeLayouts = []string{
+	time.TimeOnly,
+	"15:04",
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if t.Nanosecond() != 0 {
+			return time.Time{}, fmt.Errorf("failed to parse time %s: fractional seconds are not supported", s)
+		}
+		return t, nil
 	}
-	return t, nil
+	return time.Time{}, fmt.Errorf("failed to parse time %s: expected HH:MM[:SS]", s)
 }
 
 func parseWeekdays(weekdays []int) ([]time.Weekday, error) {
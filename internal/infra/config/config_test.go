@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"net"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -18,6 +19,16 @@ func getTestFilePath(filename string) string {
 	return filepath.Join(dir, filename)
 }
 
+// writeTestConfig writes content to a temporary config file and returns its path.
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
@@ -88,6 +99,48 @@ func TestLoadConfig(t *testing.T) {
 			path:        "./invalid.yaml",
 			expectError: true,
 		},
+		{
+			name:        "should return error for unknown global timezone",
+			path:        writeTestConfig(t, "timezone: Mars/Olympus\nblockers: []\n"),
+			expectError: true,
+		},
+		{
+			name:        "should return error for unknown blocker timezone",
+			path:        writeTestConfig(t, "blockers:\n  - name: x\n    domain: x.com\n    timezone: Mars/Olympus\n"),
+			expectError: true,
+		},
+		{
+			name: "should load domain groups",
+			path: writeTestConfig(t, "domain_groups:\n  Social: [twitter.com, x.com]\nblockers:\n  - name: social\n    domains: [reddit.com]\n    domain_groups: [social]\n"),
+			expectedConfig: &Config{
+				DomainGroups: map[string][]string{"social": {"twitter.com", "x.com"}},
+				Blockers: []Blocker{
+					{Name: "social", Domains: []string{"reddit.com"}, DomainGroups: []string{"social"}},
+				},
+			},
+		},
+		{
+			name: "should load schedules",
+			path: writeTestConfig(t, "schedules:\n  Work_Hours:\n    - type: everyday\n      ops: block\n      start: \"09:00\"\n      end: \"18:00\"\nblockers:\n  - name: x\n    domain: x.com\n    schedules: [work_hours]\n"),
+			expectedConfig: &Config{
+				Schedules: map[string][]Rule{
+					"work_hours": {{Type: "everyday", Ops: "block", Start: "09:00", End: "18:00"}},
+				},
+				Blockers: []Blocker{
+					{Name: "x", Domain: "x.com", Schedules: []string{"work_hours"}},
+				},
+			},
+		},
+		{
+			name: "should load timezones",
+			path: writeTestConfig(t, "timezone: Asia/Tokyo\nblockers:\n  - name: x\n    domain: x.com\n    timezone: America/New_York\n"),
+			expectedConfig: &Config{
+				Timezone: "Asia/Tokyo",
+				Blockers: []Blocker{
+					{Name: "x", Domain: "x.com", Timezone: "America/New_York"},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -103,6 +156,42 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestWatchConfig(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		config *Config
+		err    error
+	}
+	path := writeTestConfig(t, "server:\n  port: 8080\n")
+	results := make(chan result, 16)
+	WatchConfig(path, func(c *Config, err error) {
+		results <- result{c, err}
+	})
+
+	// A write can be seen as several events, e.g. truncating and writing, so wait for the expected result.
+	waitFor := func(match func(result) bool) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case r := <-results:
+				if match(r) {
+					return
+				}
+			case <-timeout:
+				t.Fatal("timed out waiting for the config to be reloaded")
+			}
+		}
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9090\n"), 0o600))
+	waitFor(func(r result) bool { return r.err == nil && r.config.Server.Port == 9090 })
+
+	assert.NoError(t, os.WriteFile(path, []byte("timezone: Mars/Olympus_Mons\n"), 0o600))
+	waitFor(func(r result) bool { return r.err != nil })
+}
+
 func TestBlocker_ToBlocker(t *testing.T) {
 	t.Parallel()
 
@@ -163,6 +252,362 @@ func TestBlocker_ToBlocker(t *testing.T) {
 				ForwardTo: net.IPv4(0, 0, 0, 0), // Default forward IP
 			},
 		},
+		{
+			name: "should convert wildcard domains and subdomains",
+			blocker: Blocker{
+				Name:       "twitter",
+				Domain:     "*.Twitter.com.",
+				Subdomains: []string{"mobile", "api.twitter.com"},
+			},
+			expected: domain.Blocker{
+				Domain:            "twitter.com",
+				IncludeSubdomains: true,
+				Subdomains:        []string{"mobile.twitter.com", "api.twitter.com"},
+				Rules:             []domain.BlockRule{},
+				ForwardTo:         net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should convert include_subdomains",
+			blocker: Blocker{
+				Name:              "twitter",
+				Domain:            "twitter.com",
+				IncludeSubdomains: true,
+			},
+			expected: domain.Blocker{
+				Domain:            "twitter.com",
+				IncludeSubdomains: true,
+				Rules:             []domain.BlockRule{},
+				ForwardTo:         net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should convert forward_to_v6",
+			blocker: Blocker{
+				Name:        "twitter",
+				Domain:      "twitter.com",
+				ForwardTo:   "0.0.0.0",
+				ForwardToV6: "::",
+			},
+			expected: domain.Blocker{
+				Domain:      "twitter.com",
+				Rules:       []domain.BlockRule{},
+				ForwardTo:   net.ParseIP("0.0.0.0"),
+				ForwardToV6: net.ParseIP("::"),
+			},
+		},
+		{
+			name: "should return error for IPv4 forward_to_v6",
+			blocker: Blocker{
+				Name:        "twitter",
+				Domain:      "twitter.com",
+				ForwardToV6: "0.0.0.0",
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for invalid forward_to_v6",
+			blocker: Blocker{
+				Name:        "twitter",
+				Domain:      "twitter.com",
+				ForwardToV6: "localhost",
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error without domain",
+			blocker: Blocker{
+				Name: "twitter",
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for multiple domains",
+			blocker: Blocker{
+				Name:    "twitter",
+				Domain:  "twitter.com",
+				Domains: []string{"x.com"},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for domain groups without definitions",
+			blocker: Blocker{
+				Name:         "twitter",
+				DomainGroups: []string{"social"},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for invalid domain",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.*.com",
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for invalid subdomain",
+			blocker: Blocker{
+				Name:       "twitter",
+				Domain:     "twitter.com",
+				Subdomains: []string{"-mobile"},
+			},
+			expectError: true,
+		},
+		{
+			name: "should convert allow rules",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:     "weekday",
+						Ops:      "block",
+						Start:    "09:00",
+						End:      "18:00",
+						Weekdays: []int{1, 2, 3, 4, 5},
+					},
+					{
+						Type:  "everyday",
+						Ops:   "allow",
+						Start: "12:00",
+						End:   "13:00",
+					},
+				},
+			},
+			expected: domain.Blocker{
+				Domain: "twitter.com",
+				Rules: []domain.BlockRule{
+					domain.WeekdayRule{
+						Op:       domain.BlockOpsBlock,
+						From:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+						To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+						Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+					},
+					domain.EveryDayRule{
+						Op:   domain.BlockOpsAllow,
+						From: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+						To:   time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+					},
+				},
+				ForwardTo: net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should convert overnight rules",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:     "weekday",
+						Ops:      "block",
+						Start:    "22:00",
+						End:      "06:00",
+						Weekdays: []int{5},
+					},
+				},
+			},
+			expected: domain.Blocker{
+				Domain: "twitter.com",
+				Rules: []domain.BlockRule{
+					domain.WeekdayRule{
+						Op:       domain.BlockOpsBlock,
+						From:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+						To:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+						Weekdays: []time.Weekday{time.Friday},
+					},
+				},
+				ForwardTo: net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should convert daterange rules",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:    "daterange",
+						Ops:     "block",
+						StartAt: "2026-10-31T15:00:00Z",
+						EndAt:   "2026-11-07T15:00:00Z",
+					},
+					{
+						Type:    "daterange",
+						Ops:     "allow",
+						StartAt: "2026-11-01",
+						EndAt:   "2026-11-08",
+						Start:   "12:00",
+						End:     "13:00",
+					},
+				},
+			},
+			expected: domain.Blocker{
+				Domain: "twitter.com",
+				Rules: []domain.BlockRule{
+					domain.DateRangeRule{
+						Op:    domain.BlockOpsBlock,
+						Start: time.Date(2026, 10, 31, 15, 0, 0, 0, time.UTC),
+						End:   time.Date(2026, 11, 7, 15, 0, 0, 0, time.UTC),
+					},
+					domain.DateRangeRule{
+						Op:    domain.BlockOpsAllow,
+						Start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local),
+						End:   time.Date(2026, 11, 8, 0, 0, 0, 0, time.Local),
+						Daily: true,
+						From:  time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+						To:    time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+					},
+				},
+				ForwardTo: net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should return error for daterange rule without end",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:    "daterange",
+						Ops:     "block",
+						StartAt: "2026-11-01T00:00:00Z",
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for daterange rule ending before it starts",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:    "daterange",
+						Ops:     "block",
+						StartAt: "2026-11-08T00:00:00Z",
+						EndAt:   "2026-11-01T00:00:00Z",
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "should convert recurrence rules",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:     "cron",
+						Ops:      "block",
+						Cron:     "0 9 * * 1",
+						Duration: "3h",
+					},
+					{
+						Type:     "rrule",
+						Ops:      "allow",
+						RRule:    "FREQ=MONTHLY;BYDAY=1MO",
+						StartAt:  "2026-01-05T09:00:00Z",
+						Duration: "30m",
+					},
+				},
+			},
+			expected: domain.Blocker{
+				Domain: "twitter.com",
+				Rules: []domain.BlockRule{
+					domain.RecurrenceRule{
+						Op: domain.BlockOpsBlock,
+						Recurrence: domain.CronSchedule{
+							Minutes:       1,
+							Hours:         1 << 9,
+							DaysOfMonth:   uint32(bitRange(1, 31)),
+							Months:        uint16(bitRange(1, 12)),
+							DaysOfWeek:    1 << time.Monday,
+							AnyDayOfMonth: true,
+						},
+						Duration: 3 * time.Hour,
+					},
+					domain.RecurrenceRule{
+						Op: domain.BlockOpsAllow,
+						Recurrence: domain.RRule{
+							Freq:      domain.FrequencyMonthly,
+							Interval:  1,
+							DTStart:   time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+							ByDay:     []domain.WeekdayNum{{Weekday: time.Monday, N: 1}},
+							WeekStart: time.Monday,
+						},
+						Duration: 30 * time.Minute,
+					},
+				},
+				ForwardTo: net.IPv4(0, 0, 0, 0),
+			},
+		},
+		{
+			name: "should return error for recurrence rule without duration",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type: "cron",
+						Ops:  "block",
+						Cron: "0 9 * * 1",
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for rrule rule without start_at",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:     "rrule",
+						Ops:      "block",
+						RRule:    "FREQ=DAILY",
+						Duration: "1h",
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for missing calendar file",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type: "ical",
+						Ops:  "block",
+						File: "./missing.ics",
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "should return error for unknown ops",
+			blocker: Blocker{
+				Name:   "twitter",
+				Domain: "twitter.com",
+				Rules: []Rule{
+					{
+						Type:  "everyday",
+						Ops:   "deny",
+						Start: "12:00",
+						End:   "13:00",
+					},
+				},
+			},
+			expectError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -176,3 +621,91 @@ func TestBlocker_ToBlocker(t *testing.T) {
 		})
 	}
 }
+
+func TestBlocker_ToBlocker_ICal(t *testing.T) {
+	t.Parallel()
+
+	b := Blocker{
+		Name:   "twitter",
+		Domain: "twitter.com",
+		Rules: []Rule{
+			{
+				Type: "ical",
+				Ops:  "block",
+				File: getTestFilePath("test.ics"),
+			},
+		},
+	}
+	blocker, err := b.ToBlocker(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, blocker.Rules, 1)
+	assert.IsType(t, domain.EventRule{}, blocker.Rules[0])
+	assert.True(t, blocker.IsBlocked(time.Date(2026, 1, 5, 1, 0, 0, 0, time.UTC)), "expected blocked during the first event")
+}
+
+func TestBlocker_ToBlocker_Timezone(t *testing.T) {
+	t.Parallel()
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	b := Blocker{
+		Name:     "twitter",
+		Domain:   "twitter.com",
+		Timezone: "America/New_York",
+		Rules: []Rule{
+			{
+				Type:     "rrule",
+				Ops:      "block",
+				RRule:    "FREQ=DAILY",
+				StartAt:  "2026-03-01T09:00",
+				Duration: "1h",
+			},
+		},
+	}
+	blocker, err := b.ToBlocker(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ny.String(), blocker.Location.String())
+	// 09:00 EST before and 09:00 EDT after the transition on 2026-03-08
+	assert.True(t, blocker.IsBlocked(time.Date(2026, 3, 7, 14, 30, 0, 0, time.UTC)))
+	assert.False(t, blocker.IsBlocked(time.Date(2026, 3, 8, 14, 30, 0, 0, time.UTC)))
+	assert.True(t, blocker.IsBlocked(time.Date(2026, 3, 8, 13, 30, 0, 0, time.UTC)))
+
+	b.Timezone = "Mars/Olympus"
+	_, err = b.ToBlocker(context.Background())
+	assert.Error(t, err, "expected error for unknown timezone")
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{value: "09:30", expected: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)},
+		{value: "09:30:15", expected: time.Date(0, 1, 1, 9, 30, 15, 0, time.UTC)},
+		{value: "00:00", expected: time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "23:59:59", expected: time.Date(0, 1, 1, 23, 59, 59, 0, time.UTC)},
+		{value: "24:00", expectError: true},
+		{value: "9:30", expected: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)},
+		{value: "09:30:60", expectError: true},
+		{value: "09:30:15.5", expectError: true},
+		{value: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			parsed, err := parseTime(tt.value)
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, parsed)
+			}
+		})
+	}
+}
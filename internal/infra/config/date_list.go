@@ -0,0 +1,95 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+// toDateSet collects the inline dates and the dates of the file.
+// In the file, blank lines and lines starting with "#" are ignored, and anything after the date
+// separated by whitespace or a comma (e.g. the name of the holiday) is ignored.
+// Every invalid date is reported along with the set of the valid ones.
+func (l DateList) toDateSet() (domain.DateSet, error) {
+	var dates []time.Time
+	var errs []error
+	for _, s := range l.Dates {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse date %s: %w", s, err))
+			continue
+		}
+		dates = append(dates, d)
+	}
+
+	if l.File != "" {
+		fileDates, err := readDates(l.File)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		dates = append(dates, fileDates...)
+	}
+	return domain.NewDateSet(dates...), errors.Join(errs...)
+}
+
+// readDates reads the dates of a date list file, reporting every invalid line along with the valid dates.
+func readDates(path string) ([]time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open date list file: %w", err)
+	}
+	defer f.Close()
+
+	var dates []time.Time
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		field, _, _ := strings.Cut(strings.ReplaceAll(line, ",", " "), " ")
+		d, err := time.Parse(time.DateOnly, field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: failed to parse date %s: %w", path, n, field, err))
+			continue
+		}
+		dates = append(dates, d)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to read date list file: %w", err))
+	}
+	return dates, errors.Join(errs...)
+}
+
+// exceptions resolves the names of date lists to skip and force. Names are case-insensitive.
+// Every unknown name is reported.
+func (d definitions) exceptions(skip, force []string) (domain.Exceptions, error) {
+	var e domain.Exceptions
+	var errs []error
+	for _, name := range skip {
+		set, ok := d.dateLists[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown date list: %s", name))
+			continue
+		}
+		e.Skip = e.Skip.Union(set)
+	}
+	for _, name := range force {
+		set, ok := d.dateLists[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown date list: %s", name))
+			continue
+		}
+		e.Force = e.Force.Union(set)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return domain.Exceptions{}, err
+	}
+	return e, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDateList_toDateSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		list        DateList
+		expected    domain.DateSet
+		expectError bool
+	}{
+		{
+			name:     "should collect inline dates",
+			list:     DateList{Dates: []string{"2026-05-05", "2026-05-06"}},
+			expected: domain.NewDateSet(date(2026, 5, 5), date(2026, 5, 6)),
+		},
+		{
+			name: "should collect dates from file and inline dates",
+			list: DateList{Dates: []string{"2026-05-05"}, File: getTestFilePath("holidays.txt")},
+			expected: domain.NewDateSet(
+				date(2026, 1, 1), date(2026, 1, 12), date(2026, 2, 11), date(2026, 5, 5),
+			),
+		},
+		{
+			name:        "should return error for invalid inline dates",
+			list:        DateList{Dates: []string{"2026/05/05"}},
+			expectError: true,
+		},
+		{
+			name:        "should return error for missing files",
+			list:        DateList{File: "./missing.txt"},
+			expectError: true,
+		},
+		{
+			name:        "should return error for invalid dates in files",
+			list:        DateList{File: getTestFilePath("test.yaml")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := tt.list.toDateSet()
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, set)
+			}
+		})
+	}
+}
+
+func TestDefinitions_exceptions(t *testing.T) {
+	t.Parallel()
+
+	defs, err := newDefinitions(map[string]DateList{
+		"Holidays": {Dates: []string{"2026-01-01"}},
+		"makeup":   {Dates: []string{"2026-01-03"}},
+	}, nil, nil)
+	assert.NoError(t, err)
+
+	e, err := defs.exceptions([]string{"holidays"}, []string{"MAKEUP"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Exceptions{
+		Skip:  domain.NewDateSet(date(2026, 1, 1)),
+		Force: domain.NewDateSet(date(2026, 1, 3)),
+	}, e)
+
+	_, err = defs.exceptions([]string{"unknown"}, nil)
+	assert.Error(t, err, "expected error for unknown date list")
+
+	_, err = newDefinitions(map[string]DateList{"broken": {Dates: []string{"tomorrow"}}}, nil, nil)
+	assert.Error(t, err, "expected error for invalid date list")
+}
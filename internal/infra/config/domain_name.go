@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxDomainLength is the maximum length of a domain name in text form without the trailing dot.
+const maxDomainLength = 253
+
+// parseDomain normalizes a domain name of a blocker.
+// A leading "*." is removed and reported as wildcard, meaning the domain and all of its subdomains.
+func parseDomain(s string) (name string, wildcard bool, err error) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+	if rest, ok := strings.CutPrefix(name, "*."); ok {
+		name, wildcard = rest, true
+	}
+	if err := validateDomain(name); err != nil {
+		return "", false, fmt.Errorf("invalid domain %q: %w", s, err)
+	}
+	return name, wildcard, nil
+}
+
+// parseSubdomain returns the full name of a subdomain of parent,
+// given either relative to parent such as "mobile" or as a full name such as "mobile.twitter.com".
+func parseSubdomain(s, parent string) (string, error) {
+	name := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+	if !strings.HasSuffix(name, "."+parent) {
+		name += "." + parent
+	}
+	if err := validateDomain(name); err != nil {
+		return "", fmt.Errorf("invalid subdomain %q of %s: %w", s, parent, err)
+	}
+	return name, nil
+}
+
+// domains collects the domain, the further domains and the domains of the referenced groups.
+// Group names are case-insensitive. Unknown groups are reported along with the domains that could be collected.
+func (d definitions) domains(domain string, domains, groups []string) ([]string, error) {
+	var names []string
+	if domain != "" {
+		names = append(names, domain)
+	}
+	names = append(names, domains...)
+	var errs []error
+	for _, group := range groups {
+		members, ok := d.domainGroups[strings.ToLower(group)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown domain group: %s", group))
+			continue
+		}
+		names = append(names, members...)
+	}
+	if len(names) == 0 && len(errs) == 0 {
+		return nil, fmt.Errorf("domain, domains or domain_groups must be set")
+	}
+	return names, errors.Join(errs...)
+}
+
+func validateDomain(name string) error {
+	if name == "" {
+		return fmt.Errorf("domain cannot be empty")
+	}
+	if len(name) > maxDomainLength {
+		return fmt.Errorf("domain is longer than %d characters", maxDomainLength)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("label %q must be 1 to 63 characters long", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("label %q cannot start or end with a hyphen", label)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("label %q contains invalid character %q", label, c)
+			}
+		}
+	}
+	return nil
+}
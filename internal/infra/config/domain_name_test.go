@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value            string
+		expected         string
+		expectedWildcard bool
+		expectError      bool
+	}{
+		{value: "twitter.com", expected: "twitter.com"},
+		{value: "Twitter.COM.", expected: "twitter.com"},
+		{value: "*.twitter.com", expected: "twitter.com", expectedWildcard: true},
+		{value: "_dmarc.example.com", expected: "_dmarc.example.com"},
+		{value: "localhost", expected: "localhost"},
+		{value: "", expectError: true},
+		{value: "*.", expectError: true},
+		{value: "twitter.*.com", expectError: true},
+		{value: "twitter..com", expectError: true},
+		{value: "-twitter.com", expectError: true},
+		{value: "twitter.com/path", expectError: true},
+		{value: strings.Repeat("a", 64) + ".com", expectError: true},
+		{value: strings.Repeat("a.", 127) + "com", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, wildcard, err := parseDomain(tt.value)
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, name)
+				assert.Equal(t, tt.expectedWildcard, wildcard)
+			}
+		})
+	}
+}
+
+func TestParseSubdomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{value: "mobile", expected: "mobile.twitter.com"},
+		{value: "api.mobile", expected: "api.mobile.twitter.com"},
+		{value: "Mobile.Twitter.com", expected: "mobile.twitter.com"},
+		{value: "", expectError: true},
+		{value: "*", expectError: true},
+		{value: "mobile-", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, err := parseSubdomain(tt.value, "twitter.com")
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, name)
+			}
+		})
+	}
+}
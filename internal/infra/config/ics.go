@@ -0,0 +1,324 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+// ICSCalendar provides the events of a local iCalendar (.ics) file.
+// The file is re-read whenever its modification time or size changes.
+type ICSCalendar struct {
+	path string
+	// loc is used for floating times and dates without a time zone.
+	loc *time.Location
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	events  []domain.Event
+}
+
+var _ domain.EventSource = &ICSCalendar{}
+
+// NewICSCalendar reads the calendar at path. It fails if the file cannot be read or parsed.
+func NewICSCalendar(path string, loc *time.Location) (*ICSCalendar, error) {
+	c := &ICSCalendar{path: path, loc: loc}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat calendar file: %w", err)
+	}
+	if err := c.load(info); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ICSCalendar) Events() []domain.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		slog.Error("failed to stat calendar file, keeping previous events", "path", c.path, "error", err)
+		return c.events
+	}
+	if info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.events
+	}
+	if err := c.load(info); err != nil {
+		slog.Error("failed to reload calendar file, keeping previous events", "path", c.path, "error", err)
+	}
+	return c.events
+}
+
+// load reads the file and records info even on failure, so that a broken file is not re-read until it changes again.
+func (c *ICSCalendar) load(info os.FileInfo) error {
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to open calendar file: %w", err)
+	}
+	defer f.Close()
+
+	events, err := parseICS(f, c.loc)
+	if err != nil {
+		return fmt.Errorf("failed to parse calendar file %s: %w", c.path, err)
+	}
+	slog.Info("Calendar loaded", "path", c.path, "events", len(events))
+	c.events = events
+	return nil
+}
+
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+type icsEvent struct {
+	uid          string
+	start        time.Time
+	allDay       bool
+	end          time.Time
+	duration     time.Duration
+	rrule        string
+	exdates      []time.Time
+	recurrenceID time.Time
+	cancelled    bool
+}
+
+// parseICS parses the VEVENT components of an iCalendar stream (RFC 5545).
+// Overridden occurrences (RECURRENCE-ID) replace the occurrence of the recurring event with the same UID,
+// and cancelled events are ignored. RDATE and VTIMEZONE definitions are not supported; TZID must be an IANA name.
+// Events whose RRULE is not supported, e.g. with BYSETPOS, are logged and skipped, keeping the rest of the calendar.
+func parseICS(r io.Reader, loc *time.Location) ([]domain.Event, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []*icsEvent
+	var cur *icsEvent
+	depth := 0 // nesting depth of components inside the current VEVENT, e.g. VALARM
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseICSProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT") && cur == nil:
+			cur = &icsEvent{}
+		case cur == nil:
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			parsed = append(parsed, cur)
+			cur = nil
+		case depth > 0:
+		default:
+			if err := cur.set(p, loc); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", i+1, p.name, err)
+			}
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+
+	masters := map[string]*icsEvent{}
+	for _, e := range parsed {
+		if e.rrule != "" && e.recurrenceID.IsZero() {
+			masters[e.uid] = e
+		}
+	}
+	for _, e := range parsed {
+		if m, ok := masters[e.uid]; ok && !e.recurrenceID.IsZero() {
+			m.exdates = append(m.exdates, e.recurrenceID)
+		}
+	}
+
+	var events []domain.Event
+	for _, e := range parsed {
+		if e.cancelled || e.start.IsZero() {
+			continue
+		}
+		event, err := e.toEvent()
+		if err != nil {
+			slog.Warn("Skipping calendar event", "uid", e.uid, "error", err)
+			continue
+		}
+		if event.Duration > 0 {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (e *icsEvent) set(p icsProperty, loc *time.Location) error {
+	var err error
+	switch p.name {
+	case "UID":
+		e.uid = p.value
+	case "DTSTART":
+		e.start, err = parseICSTime(p, loc)
+		e.allDay = p.params["VALUE"] == "DATE" || len(p.value) == len("20060102")
+	case "DTEND":
+		e.end, err = parseICSTime(p, loc)
+	case "DURATION":
+		e.duration, err = parseICSDuration(p.value)
+	case "RRULE":
+		e.rrule = p.value
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			ex, err := parseICSTime(icsProperty{name: p.name, params: p.params, value: v}, loc)
+			if err != nil {
+				return err
+			}
+			e.exdates = append(e.exdates, ex)
+		}
+	case "RECURRENCE-ID":
+		e.recurrenceID, err = parseICSTime(p, loc)
+	case "STATUS":
+		e.cancelled = strings.EqualFold(p.value, "CANCELLED")
+	}
+	return err
+}
+
+func (e *icsEvent) toEvent() (domain.Event, error) {
+	duration := e.duration
+	switch {
+	case !e.end.IsZero():
+		duration = e.end.Sub(e.start)
+	case duration == 0 && e.allDay:
+		duration = 24 * time.Hour
+	}
+	event := domain.Event{
+		Start:    e.start,
+		Duration: duration,
+		ExDates:  e.exdates,
+	}
+	if e.rrule != "" && e.recurrenceID.IsZero() {
+		rrule, err := parseRRule(e.rrule, e.start)
+		if err != nil {
+			return domain.Event{}, fmt.Errorf("invalid RRULE: %w", err)
+		}
+		// DTSTART is always the first instance and counts toward COUNT, but the event covers it on its own
+		// and the rule only yields it if it matches the rule.
+		if rrule.Count > 0 && !rrule.Next(e.start.Add(-time.Nanosecond)).Equal(e.start) {
+			rrule.Count--
+			if rrule.Count == 0 {
+				return event, nil
+			}
+		}
+		event.Recurrence = rrule
+	}
+	return event, nil
+}
+
+// unfoldICS joins continuation lines, which start with a space or tab.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	// The value starts at the first colon outside of quoted parameter values.
+	colon := -1
+	quoted := false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("missing value in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+// parseICSTime parses a DATE or DATE-TIME value. Floating values are interpreted in loc.
+func parseICSTime(p icsProperty, loc *time.Location) (time.Time, error) {
+	if tzid, ok := p.params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q: %w", tzid, err)
+		}
+		loc = l
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		return time.Parse("20060102T150405Z", p.value)
+	}
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		return time.ParseInLocation("20060102", p.value, loc)
+	}
+	return time.ParseInLocation("20060102T150405", p.value, loc)
+}
+
+var icsDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration parses a DURATION value such as "PT1H30M" or "P1D".
+func parseICSDuration(s string) (time.Duration, error) {
+	m := icsDurationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseICS(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(getTestFilePath("test.ics"))
+	if err != nil {
+		t.Fatalf("failed to open test calendar: %v", err)
+	}
+	defer f.Close()
+
+	events, err := parseICS(f, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, events, 4, "cancelled event should be skipped")
+	rule := domain.EventRule{Op: domain.BlockOpsBlock, Source: staticSource(events)}
+
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "should be active during the first occurrence",
+			time:     time.Date(2026, 1, 5, 10, 0, 0, 0, jst),
+			expected: true,
+		},
+		{
+			name:     "should not be active after DTEND",
+			time:     time.Date(2026, 1, 5, 12, 0, 0, 0, jst),
+			expected: false,
+		},
+		{
+			name:     "should not be active on excluded dates",
+			time:     time.Date(2026, 1, 7, 10, 0, 0, 0, jst),
+			expected: false,
+		},
+		{
+			name:     "should be active on recurrences",
+			time:     time.Date(2026, 1, 14, 11, 59, 0, 0, jst),
+			expected: true,
+		},
+		{
+			name:     "should not be active at the original time of an overridden occurrence",
+			time:     time.Date(2026, 1, 12, 10, 0, 0, 0, jst),
+			expected: false,
+		},
+		{
+			name:     "should be active at the new time of an overridden occurrence",
+			time:     time.Date(2026, 1, 12, 14, 0, 0, 0, jst),
+			expected: true,
+		},
+		{
+			name:     "should be active during all-day events",
+			time:     time.Date(2026, 11, 7, 23, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should not be active after all-day events",
+			time:     time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should be active at a DTSTART not matching the rule",
+			time:     time.Date(2026, 1, 7, 9, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should count a DTSTART not matching the rule toward COUNT",
+			time:     time.Date(2026, 1, 19, 9, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "should not be active after COUNT occurrences including DTSTART",
+			time:     time.Date(2026, 1, 26, 9, 30, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "should not be active during cancelled events",
+			time:     time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rule.IsActive(tt.time))
+		})
+	}
+}
+
+func TestParseICS_FirstOccurrenceOverridden(t *testing.T) {
+	t.Parallel()
+
+	ics := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:standup@example.com
+DTSTART:20260105T090000Z
+DURATION:PT1H
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:standup@example.com
+RECURRENCE-ID:20260105T090000Z
+DTSTART:20260105T140000Z
+DURATION:PT1H
+END:VEVENT
+END:VCALENDAR
+`
+	events, err := parseICS(strings.NewReader(ics), time.UTC)
+	assert.NoError(t, err)
+	rule := domain.EventRule{Op: domain.BlockOpsBlock, Source: staticSource(events)}
+	assert.False(t, rule.IsActive(time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)), "expected the original time to be free")
+	assert.True(t, rule.IsActive(time.Date(2026, 1, 5, 14, 30, 0, 0, time.UTC)), "expected the new time to be blocked")
+	assert.True(t, rule.IsActive(time.Date(2026, 1, 6, 9, 30, 0, 0, time.UTC)), "expected later occurrences to be blocked")
+}
+
+func TestParseICS_UnsupportedRecurrence(t *testing.T) {
+	t.Parallel()
+
+	ics := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:last-friday@example.com
+DTSTART:20260130T090000Z
+DURATION:PT1H
+RRULE:FREQ=MONTHLY;BYDAY=FR;BYSETPOS=-1
+END:VEVENT
+BEGIN:VEVENT
+UID:hourly@example.com
+DTSTART:20260105T090000Z
+DURATION:PT1H
+RRULE:FREQ=DAILY;BYHOUR=9,15
+END:VEVENT
+BEGIN:VEVENT
+UID:focus@example.com
+DTSTART:20260105T090000Z
+DURATION:PT1H
+END:VEVENT
+END:VCALENDAR
+`
+	events, err := parseICS(strings.NewReader(ics), time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Event{
+		{Start: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), Duration: time.Hour},
+	}, events, "expected events with unsupported recurrence rules to be skipped")
+}
+
+func TestParseICS_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{
+			name: "should return error for unterminated events",
+			ics:  "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260105T090000Z\n",
+		},
+		{
+			name: "should return error for invalid dates",
+			ics:  "BEGIN:VEVENT\nDTSTART:2026-01-05\nEND:VEVENT\n",
+		},
+		{
+			name: "should return error for unknown time zones",
+			ics:  "BEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20260105T090000\nEND:VEVENT\n",
+		},
+		{
+			name: "should return error for lines without value",
+			ics:  "BEGIN:VEVENT\nDTSTART\nEND:VEVENT\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseICS(strings.NewReader(tt.ics), time.UTC)
+			assert.Error(t, err, "expected error but got none")
+		})
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{value: "PT1H30M", expected: 90 * time.Minute},
+		{value: "P1D", expected: 24 * time.Hour},
+		{value: "P1W", expected: 7 * 24 * time.Hour},
+		{value: "P1DT12H", expected: 36 * time.Hour},
+		{value: "PT45S", expected: 45 * time.Second},
+		{value: "-PT10M", expected: -10 * time.Minute},
+		{value: "P", expectError: true},
+		{value: "PT", expectError: true},
+		{value: "1H", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, err := parseICSDuration(tt.value)
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestICSCalendar_Events(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "focus.ics")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write calendar: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set modification time: %v", err)
+		}
+	}
+	event := func(start string) string {
+		return "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:" + start + "\nDURATION:PT1H\nEND:VEVENT\nEND:VCALENDAR\n"
+	}
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	write(event("20260105T090000Z"), modTime)
+	c, err := NewICSCalendar(path, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, c.Events(), 1)
+	assert.Equal(t, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), c.Events()[0].Start)
+
+	// should re-read the file when it changes
+	write(event("20260106T090000Z"), modTime.Add(time.Minute))
+	assert.Equal(t, time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC), c.Events()[0].Start)
+
+	// should keep the previous events when the file becomes invalid
+	write("BEGIN:VEVENT\n", modTime.Add(2*time.Minute))
+	assert.Equal(t, time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC), c.Events()[0].Start)
+
+	// should keep the previous events when the file is removed
+	assert.NoError(t, os.Remove(path))
+	assert.Len(t, c.Events(), 1)
+
+	_, err = NewICSCalendar(path, time.UTC)
+	assert.Error(t, err, "expected error for missing file")
+}
+
+type staticSource []domain.Event
+
+func (s staticSource) Events() []domain.Event {
+	return s
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Problems returns the individual problems of an error returned by LoadConfig or BlockerFactory.GenBlockers,
+// which report every problem they find at once.
+func Problems(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var problems []error
+		for _, e := range joined.Unwrap() {
+			problems = append(problems, Problems(e)...)
+		}
+		return problems
+	}
+	return []error{err}
+}
+
+// wrapProblems prefixes every problem of err with the formatted context, keeping the problems separate.
+func wrapProblems(err error, format string, args ...any) error {
+	var wrapped []error
+	for _, p := range Problems(err) {
+		wrapped = append(wrapped, fmt.Errorf(format+": %w", append(args, p)...))
+	}
+	return errors.Join(wrapped...)
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblems(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := errors.New("a"), errors.New("b"), errors.New("c")
+	assert.Nil(t, Problems(nil))
+	assert.Equal(t, []error{a}, Problems(a))
+	assert.Equal(t, []error{a, b, c}, Problems(errors.Join(a, errors.Join(b, c))))
+}
+
+func TestWrapProblems(t *testing.T) {
+	t.Parallel()
+
+	err := wrapProblems(errors.Join(errors.New("a"), errors.New("b")), "blocker %s", "x")
+	problems := Problems(err)
+	assert.Len(t, problems, 2)
+	assert.EqualError(t, problems[0], "blocker x: a")
+	assert.EqualError(t, problems[1], "blocker x: b")
+	assert.Nil(t, wrapProblems(nil, "blocker %s", "x"))
+}
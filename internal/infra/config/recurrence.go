@@ -0,0 +1,232 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// parseCron parses a standard five field cron expression (minute, hour, day of month, month, day of week).
+// Fields support "*", lists, ranges, steps and month or weekday names. 7 is accepted as Sunday.
+func parseCron(expr string) (domain.CronSchedule, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return domain.CronSchedule{}, fmt.Errorf("expected 5 fields but got %d", len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return domain.CronSchedule{}, fmt.Errorf("invalid minute field %q: %w", fields[0], err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return domain.CronSchedule{}, fmt.Errorf("invalid hour field %q: %w", fields[1], err)
+	}
+	dom, err := parseCronField(fields[2], 1, 31, nil)
+	if err != nil {
+		return domain.CronSchedule{}, fmt.Errorf("invalid day of month field %q: %w", fields[2], err)
+	}
+	months, err := parseCronField(fields[3], 1, 12, cronMonthNames)
+	if err != nil {
+		return domain.CronSchedule{}, fmt.Errorf("invalid month field %q: %w", fields[3], err)
+	}
+	dow, err := parseCronField(fields[4], 0, 7, cronWeekdayNames)
+	if err != nil {
+		return domain.CronSchedule{}, fmt.Errorf("invalid day of week field %q: %w", fields[4], err)
+	}
+	if dow&(1<<7) != 0 {
+		dow = dow&^(1<<7) | 1
+	}
+
+	return domain.CronSchedule{
+		Minutes:       minutes,
+		Hours:         uint32(hours),
+		DaysOfMonth:   uint32(dom),
+		Months:        uint16(months),
+		DaysOfWeek:    uint8(dow),
+		AnyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		AnyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a single cron field into a bit set of the values between min and max.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepStr)
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = s
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, min, max, names); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = parseCronValue(hiStr, min, max, names); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("invalid range %q", rng)
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+	}
+	return v, nil
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRRule parses the value of an iCalendar RRULE property such as "FREQ=MONTHLY;BYDAY=1MO".
+// The "RRULE:" prefix is optional.
+func parseRRule(s string, dtstart time.Time) (domain.RRule, error) {
+	r := domain.RRule{
+		Interval:  1,
+		DTStart:   dtstart,
+		WeekStart: time.Monday,
+	}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return domain.RRule{}, fmt.Errorf("invalid rrule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = domain.Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			r.Until, err = parseRRuleUntil(value, dtstart.Location())
+		case "WKST":
+			wd, ok := rruleWeekdays[strings.ToUpper(value)]
+			if !ok {
+				err = fmt.Errorf("invalid weekday %q", value)
+			}
+			r.WeekStart = wd
+		case "BYMONTH":
+			err = parseRRuleList(value, func(v int) { r.ByMonth = append(r.ByMonth, time.Month(v)) })
+		case "BYMONTHDAY":
+			err = parseRRuleList(value, func(v int) { r.ByMonthDay = append(r.ByMonthDay, v) })
+		case "BYDAY":
+			r.ByDay, err = parseRRuleByDay(value)
+		default:
+			err = fmt.Errorf("unsupported rrule part")
+		}
+		if err != nil {
+			return domain.RRule{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	if err := r.Validate(); err != nil {
+		return domain.RRule{}, err
+	}
+	return r, nil
+}
+
+// parseRRuleUntil parses UNTIL as a UTC date-time, a floating date-time in loc, or a date meaning the whole day.
+func parseRRuleUntil(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", s, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102", s, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func parseRRuleList(s string, add func(int)) error {
+	for _, item := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(item)
+		if err != nil {
+			return err
+		}
+		add(v)
+	}
+	return nil
+}
+
+func parseRRuleByDay(s string) ([]domain.WeekdayNum, error) {
+	var days []domain.WeekdayNum
+	for _, item := range strings.Split(s, ",") {
+		item = strings.ToUpper(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		wd, ok := rruleWeekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		n := 0
+		if ord := item[:len(item)-2]; ord != "" {
+			var err error
+			if n, err = strconv.Atoi(ord); err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid weekday ordinal %q", ord)
+			}
+		}
+		days = append(days, domain.WeekdayNum{Weekday: wd, N: n})
+	}
+	return days, nil
+}
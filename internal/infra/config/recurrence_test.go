@@ -0,0 +1,230 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func bits(values ...int) uint64 {
+	var b uint64
+	for _, v := range values {
+		b |= 1 << v
+	}
+	return b
+}
+
+func bitRange(min, max int) uint64 {
+	var b uint64
+	for v := min; v <= max; v++ {
+		b |= 1 << v
+	}
+	return b
+}
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expr        string
+		expected    domain.CronSchedule
+		expectError bool
+	}{
+		{
+			name: "should parse a simple expression",
+			expr: "0 9 * * 1-5",
+			expected: domain.CronSchedule{
+				Minutes:       bits(0),
+				Hours:         uint32(bits(9)),
+				DaysOfMonth:   uint32(bitRange(1, 31)),
+				Months:        uint16(bitRange(1, 12)),
+				DaysOfWeek:    uint8(bitRange(1, 5)),
+				AnyDayOfMonth: true,
+			},
+		},
+		{
+			name: "should parse lists, steps and names",
+			expr: "*/15 8-18/2 1,15 jan-mar SAT,7",
+			expected: domain.CronSchedule{
+				Minutes:     bits(0, 15, 30, 45),
+				Hours:       uint32(bits(8, 10, 12, 14, 16, 18)),
+				DaysOfMonth: uint32(bits(1, 15)),
+				Months:      uint16(bits(1, 2, 3)),
+				DaysOfWeek:  uint8(bits(0, 6)),
+			},
+		},
+		{
+			name: "should parse a step starting at a value",
+			expr: "5/20 0 * * *",
+			expected: domain.CronSchedule{
+				Minutes:       bits(5, 25, 45),
+				Hours:         uint32(bits(0)),
+				DaysOfMonth:   uint32(bitRange(1, 31)),
+				Months:        uint16(bitRange(1, 12)),
+				DaysOfWeek:    uint8(bitRange(0, 6)),
+				AnyDayOfMonth: true,
+				AnyDayOfWeek:  true,
+			},
+		},
+		{
+			name: "should parse macros",
+			expr: "@weekly",
+			expected: domain.CronSchedule{
+				Minutes:       bits(0),
+				Hours:         uint32(bits(0)),
+				DaysOfMonth:   uint32(bitRange(1, 31)),
+				Months:        uint16(bitRange(1, 12)),
+				DaysOfWeek:    uint8(bits(0)),
+				AnyDayOfMonth: true,
+			},
+		},
+		{
+			name:        "should return error for wrong number of fields",
+			expr:        "0 9 * *",
+			expectError: true,
+		},
+		{
+			name:        "should return error for out of range values",
+			expr:        "0 24 * * *",
+			expectError: true,
+		},
+		{
+			name:        "should return error for reversed ranges",
+			expr:        "0 9 * * 5-1",
+			expectError: true,
+		},
+		{
+			name:        "should return error for invalid steps",
+			expr:        "*/0 9 * * *",
+			expectError: true,
+		},
+		{
+			name:        "should return error for unknown names",
+			expr:        "0 9 * * MOO",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseCron(tt.expr)
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	t.Parallel()
+
+	dtstart := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		rrule       string
+		expected    domain.RRule
+		expectError bool
+	}{
+		{
+			name:  "should parse first Monday of every month",
+			rrule: "FREQ=MONTHLY;BYDAY=1MO",
+			expected: domain.RRule{
+				Freq:      domain.FrequencyMonthly,
+				Interval:  1,
+				DTStart:   dtstart,
+				ByDay:     []domain.WeekdayNum{{Weekday: time.Monday, N: 1}},
+				WeekStart: time.Monday,
+			},
+		},
+		{
+			name:  "should parse every other week with prefix",
+			rrule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;WKST=SU",
+			expected: domain.RRule{
+				Freq:      domain.FrequencyWeekly,
+				Interval:  2,
+				DTStart:   dtstart,
+				ByDay:     []domain.WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Friday}},
+				WeekStart: time.Sunday,
+			},
+		},
+		{
+			name:        "should return error for weekday ordinals in weekly rules",
+			rrule:       "FREQ=WEEKLY;BYDAY=-1FR",
+			expectError: true,
+		},
+		{
+			name:  "should parse count, bymonth and bymonthday",
+			rrule: "FREQ=YEARLY;COUNT=3;BYMONTH=1,7;BYMONTHDAY=1,-1",
+			expected: domain.RRule{
+				Freq:       domain.FrequencyYearly,
+				Interval:   1,
+				DTStart:    dtstart,
+				Count:      3,
+				ByMonth:    []time.Month{time.January, time.July},
+				ByMonthDay: []int{1, -1},
+				WeekStart:  time.Monday,
+			},
+		},
+		{
+			name:  "should parse until as UTC date-time",
+			rrule: "FREQ=DAILY;UNTIL=20260131T090000Z",
+			expected: domain.RRule{
+				Freq:      domain.FrequencyDaily,
+				Interval:  1,
+				DTStart:   dtstart,
+				Until:     time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+				WeekStart: time.Monday,
+			},
+		},
+		{
+			name:  "should parse until as a whole date",
+			rrule: "FREQ=DAILY;UNTIL=20260131",
+			expected: domain.RRule{
+				Freq:      domain.FrequencyDaily,
+				Interval:  1,
+				DTStart:   dtstart,
+				Until:     time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC),
+				WeekStart: time.Monday,
+			},
+		},
+		{
+			name:        "should return error without frequency",
+			rrule:       "INTERVAL=2",
+			expectError: true,
+		},
+		{
+			name:        "should return error for unsupported parts",
+			rrule:       "FREQ=DAILY;BYHOUR=9",
+			expectError: true,
+		},
+		{
+			name:        "should return error for malformed parts",
+			rrule:       "FREQ=DAILY;INTERVAL",
+			expectError: true,
+		},
+		{
+			name:        "should return error for invalid weekdays",
+			rrule:       "FREQ=WEEKLY;BYDAY=XX",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseRRule(tt.rrule, dtstart)
+			if tt.expectError {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
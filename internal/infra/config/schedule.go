@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// scheduleRuleType is the type of a rule that inlines the rules of a named schedule.
+const scheduleRuleType = "schedule"
+
+// rules returns the rules of the referenced schedules followed by the inline rules,
+// with rules of type "schedule" replaced by the rules of the named schedule. Schedule names are case-insensitive.
+// Every reference that cannot be resolved is reported along with the rules that could be.
+func (d definitions) rules(schedules []string, inline []Rule) ([]Rule, error) {
+	var rules []Rule
+	var errs []error
+	for _, name := range schedules {
+		resolved, err := d.schedule(name, nil)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		rules = append(rules, resolved...)
+	}
+	resolved, err := d.expandRules(inline, nil)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return append(rules, resolved...), errors.Join(errs...)
+}
+
+// schedule returns the expanded rules of the named schedule. path holds the schedules being expanded
+// to detect cycles.
+func (d definitions) schedule(name string, path []string) ([]Rule, error) {
+	key := strings.ToLower(name)
+	for i, p := range path {
+		if p == key {
+			return nil, fmt.Errorf("schedule cycle: %s", strings.Join(append(path[i:], key), " -> "))
+		}
+	}
+	rules, ok := d.schedules[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown schedule: %s", name)
+	}
+	return d.expandRules(rules, append(path, key))
+}
+
+func (d definitions) expandRules(rules []Rule, path []string) ([]Rule, error) {
+	var expanded []Rule
+	var errs []error
+	for _, r := range rules {
+		if r.Type != scheduleRuleType {
+			expanded = append(expanded, r)
+			continue
+		}
+		if r.Schedule == "" {
+			errs = append(errs, fmt.Errorf("schedule must be set for rules of type %s", scheduleRuleType))
+			continue
+		}
+		resolved, err := d.schedule(r.Schedule, path[:len(path):len(path)])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		expanded = append(expanded, resolved...)
+	}
+	return expanded, errors.Join(errs...)
+}
+
+// validateSchedules checks that every schedule only references known schedules without cycles,
+// including schedules no blocker references.
+func (d definitions) validateSchedules() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(d.schedules)) {
+		if _, err := d.schedule(name, nil); err != nil {
+			errs = append(errs, wrapProblems(err, "invalid schedule %s", name))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitions_rules(t *testing.T) {
+	t.Parallel()
+
+	workHours := Rule{Type: "weekday", Ops: "block", Start: "09:00", End: "18:00", Weekdays: []int{1, 2, 3, 4, 5}}
+	lunch := Rule{Type: "everyday", Ops: "allow", Start: "12:00", End: "13:00"}
+	night := Rule{Type: "everyday", Ops: "block", Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name        string
+		schedules   map[string][]Rule
+		refs        []string
+		inline      []Rule
+		expected    []Rule
+		expectError string
+	}{
+		{
+			name:     "should return inline rules without schedules",
+			inline:   []Rule{night},
+			expected: []Rule{night},
+		},
+		{
+			name:      "should put referenced schedules before inline rules",
+			schedules: map[string][]Rule{"work_hours": {workHours}, "night": {night}},
+			refs:      []string{"work_hours", "night"},
+			inline:    []Rule{lunch},
+			expected:  []Rule{workHours, night, lunch},
+		},
+		{
+			name:      "should replace schedule rules in place",
+			schedules: map[string][]Rule{"work_hours": {workHours}},
+			inline:    []Rule{night, {Type: "schedule", Schedule: "work_hours"}, lunch},
+			expected:  []Rule{night, workHours, lunch},
+		},
+		{
+			name: "should expand nested schedules",
+			schedules: map[string][]Rule{
+				"work_hours": {workHours},
+				"workday":    {{Type: "schedule", Schedule: "work_hours"}, lunch},
+			},
+			refs:     []string{"workday"},
+			expected: []Rule{workHours, lunch},
+		},
+		{
+			name: "should allow referencing a schedule more than once",
+			schedules: map[string][]Rule{
+				"work_hours": {workHours},
+				"workday":    {{Type: "schedule", Schedule: "work_hours"}, {Type: "schedule", Schedule: "work_hours"}},
+			},
+			refs:     []string{"workday"},
+			expected: []Rule{workHours, workHours},
+		},
+		{
+			name:      "should resolve names case-insensitively",
+			schedules: map[string][]Rule{"Work_Hours": {workHours}},
+			refs:      []string{"WORK_HOURS"},
+			expected:  []Rule{workHours},
+		},
+		{
+			name:        "should return error for unknown schedule",
+			refs:        []string{"work_hours"},
+			expectError: "unknown schedule: work_hours",
+		},
+		{
+			name:        "should return error for unknown schedule of a rule",
+			inline:      []Rule{{Type: "schedule", Schedule: "work_hours"}},
+			expectError: "unknown schedule: work_hours",
+		},
+		{
+			name:        "should return error for schedule rule without name",
+			inline:      []Rule{{Type: "schedule"}},
+			expectError: "schedule must be set",
+		},
+		{
+			name: "should return error for cycles",
+			schedules: map[string][]Rule{
+				"a": {{Type: "schedule", Schedule: "b"}},
+				"b": {night, {Type: "schedule", Schedule: "a"}},
+			},
+			refs:        []string{"a"},
+			expectError: "schedule cycle: a -> b -> a",
+		},
+		{
+			name:        "should return error for schedules referencing themselves",
+			schedules:   map[string][]Rule{"a": {{Type: "schedule", Schedule: "a"}}},
+			inline:      []Rule{{Type: "schedule", Schedule: "a"}},
+			expectError: "schedule cycle: a -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := definitions{schedules: map[string][]Rule{}}
+			for name, rules := range tt.schedules {
+				defs.schedules[strings.ToLower(name)] = rules
+			}
+			rules, err := defs.rules(tt.refs, tt.inline)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err, "expected no error but got one")
+				assert.Equal(t, tt.expected, rules)
+			}
+		})
+	}
+}
+
+func TestNewDefinitions_Schedules(t *testing.T) {
+	t.Parallel()
+
+	_, err := newDefinitions(nil, nil, map[string][]Rule{
+		"work_hours": {{Type: "weekday", Ops: "block", Start: "09:00", End: "18:00", Weekdays: []int{1}}},
+	})
+	assert.NoError(t, err)
+
+	_, err = newDefinitions(nil, nil, map[string][]Rule{
+		"unused": {{Type: "schedule", Schedule: "unused"}},
+	})
+	assert.ErrorContains(t, err, "invalid schedule unused", "expected cycles to be detected in unreferenced schedules")
+}
@@ -0,0 +1,37 @@
+package presentation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+// maxAdblockExpires is the longest expiry announced in AdBlock filter lists.
+const maxAdblockExpires = 7 * 24 * time.Hour
+
+// renderAdblock renders an AdBlock-style filter list, as used by AdGuard Home and browser extensions.
+// Wildcards become "||domain^" rules, which include all subdomains. Other names, including known subdomains,
+// become "|domain^" rules, which only match the name itself.
+// The list expires after expires, the time until the next schedule change, rounded down to whole hours
+// because that is the finest unit clients understand.
+func renderAdblock(entries []domain.HostsEntry, expires time.Duration) string {
+	hours := int(expires / time.Hour)
+	if hours < 1 {
+		hours = 1
+	}
+
+	var b uniqueLines
+	b.WriteString("! Title: Sinkhole-Detox\n")
+	b.WriteString(fmt.Sprintf("! Expires: %d hours\n", hours))
+	for _, entry := range entries {
+		if entry.IncludeSubdomains {
+			b.WriteLine("||" + entry.Domain + "^")
+			continue
+		}
+		for _, name := range entry.Names() {
+			b.WriteLine("|" + name + "^")
+		}
+	}
+	return b.String()
+}
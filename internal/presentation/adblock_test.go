@@ -0,0 +1,62 @@
+package presentation
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAdblock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expires  time.Duration
+		expected string
+	}{
+		{
+			name:    "should render the entries with the expiry in hours",
+			expires: 5 * time.Hour,
+			expected: "! Title: Sinkhole-Detox\n" +
+				"! Expires: 5 hours\n" +
+				"||twitter.com^\n" +
+				"|x.com^\n" +
+				"|reddit.com^\n",
+		},
+		{
+			name:    "should round the expiry down",
+			expires: 90 * time.Minute,
+			expected: "! Title: Sinkhole-Detox\n" +
+				"! Expires: 1 hours\n" +
+				"||twitter.com^\n" +
+				"|x.com^\n" +
+				"|reddit.com^\n",
+		},
+		{
+			name:    "should expire after an hour at the earliest",
+			expires: 10 * time.Minute,
+			expected: "! Title: Sinkhole-Detox\n" +
+				"! Expires: 1 hours\n" +
+				"||twitter.com^\n" +
+				"|x.com^\n" +
+				"|reddit.com^\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderAdblock(exampleEntries(), tt.expires))
+		})
+	}
+
+	duplicated := append(exampleEntries()[:1], exampleEntries()[:1]...)
+	assert.Equal(t, "! Title: Sinkhole-Detox\n! Expires: 1 hours\n||twitter.com^\n", renderAdblock(duplicated, time.Hour),
+		"expected duplicated domains to be written once")
+
+	exact := []domain.HostsEntry{{IP: net.IPv4(0, 0, 0, 0), Domain: "x.com", Subdomains: []string{"mobile.x.com"}}}
+	assert.Equal(t, "! Title: Sinkhole-Detox\n! Expires: 1 hours\n|x.com^\n|mobile.x.com^\n", renderAdblock(exact, time.Hour),
+		"expected names without wildcard to only match themselves")
+}
@@ -0,0 +1,52 @@
+package presentation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+// maxCacheAge is the longest time clients may cache a list, so that configuration changes
+// are picked up even if the schedule does not change for a long time.
+const maxCacheAge = time.Hour
+
+// etag returns an entity tag for the entries rendered in the named format, so that lists of the same blocked domains
+// get the same tag. It is weak because parts of the body that change with time, such as the expiry of AdBlock lists
+// and the serial of RPZ zones, are left out.
+func etag(format string, entries []domain.HostsEntry) string {
+	h := sha256.New()
+	fmt.Fprintln(h, format)
+	for _, e := range entries {
+		fmt.Fprintln(h, e.Domain, e.IP, e.IPv6, e.IncludeSubdomains, strings.Join(e.Subdomains, ","))
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header value matches tag.
+// As required for If-None-Match, tags are compared weakly, ignoring a "W/" prefix.
+func etagMatches(ifNoneMatch, tag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setCacheHeaders lets clients cache a response generated at t for age,
+// the time until the next schedule change, so that they refresh when the list changes.
+func setCacheHeaders(h http.Header, t time.Time, age time.Duration) {
+	seconds := int64(age / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	h.Set("Cache-Control", "max-age="+strconv.FormatInt(seconds, 10))
+	h.Set("Expires", t.Add(time.Duration(seconds)*time.Second).UTC().Format(http.TimeFormat))
+}
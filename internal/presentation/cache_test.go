@@ -0,0 +1,52 @@
+package presentation
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtag(t *testing.T) {
+	t.Parallel()
+
+	entries := exampleEntries()
+	assert.Equal(t, etag("domains", entries), etag("domains", exampleEntries()))
+	assert.NotEqual(t, etag("domains", entries), etag("domains", nil))
+	assert.NotEqual(t, etag("domains", entries), etag("hosts", entries))
+	assert.NotEqual(t, etag("domains", entries), etag("domains", entries[1:]))
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag("domains", nil))
+}
+
+func TestEtagMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{name: "should match the same tag", ifNoneMatch: `"abc"`, expected: true},
+		{name: "should match a tag in a list", ifNoneMatch: `"xyz", "abc"`, expected: true},
+		{name: "should match a weak tag", ifNoneMatch: `W/"abc"`, expected: true},
+		{name: "should match any tag", ifNoneMatch: `*`, expected: true},
+		{name: "should not match another tag", ifNoneMatch: `"xyz"`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, etagMatches(tt.ifNoneMatch, `"abc"`))
+		})
+	}
+}
+
+func TestSetCacheHeaders(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 6, 10, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	h := http.Header{}
+	setCacheHeaders(h, at, 90*time.Second+500*time.Millisecond)
+	assert.Equal(t, "max-age=90", h.Get("Cache-Control"), "expected the age rounded down")
+	assert.Equal(t, "Mon, 06 Jan 2025 01:01:30 GMT", h.Get("Expires"))
+}
@@ -0,0 +1,179 @@
+package presentation
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// view is the blocking state at a point in time that a format renders.
+type view struct {
+	at        time.Time
+	entries   []domain.HostsEntry
+	generator *domain.HostsGenerator
+	// loadedAt is the time the blockers were loaded.
+	loadedAt time.Time
+	// serials keeps the serial of RPZ zones from decreasing between views, nil for a view rendered once.
+	serials *serials
+}
+
+// format renders the blocking state for a kind of DNS server or blocker.
+type format struct {
+	contentType string
+	render      func(v view) string
+}
+
+// untilNextChange returns the time until the next schedule change, or max if there is none before.
+func (v view) untilNextChange(max time.Duration) time.Duration {
+	if next := v.generator.NextChange(v.at, max); !next.IsZero() {
+		return next.Sub(v.at)
+	}
+	return max
+}
+
+// entriesOnly adapts a renderer of the blocked entries to a format.
+func entriesOnly(render func(entries []domain.HostsEntry) string) func(v view) string {
+	return func(v view) string {
+		return render(v.entries)
+	}
+}
+
+// formats are the output formats selectable with the "format" query parameter.
+var formats = map[string]format{
+	"domains":          {contentType: echo.MIMETextPlainCharsetUTF8, render: entriesOnly(renderDomains)},
+	"hosts":            {contentType: echo.MIMETextPlainCharsetUTF8, render: entriesOnly(renderHosts)},
+	"dnsmasq":          {contentType: echo.MIMETextPlainCharsetUTF8, render: entriesOnly(renderDnsmasq)},
+	"unbound":          {contentType: echo.MIMETextPlainCharsetUTF8, render: entriesOnly(renderUnbound)},
+	"unbound-nxdomain": {contentType: echo.MIMETextPlainCharsetUTF8, render: entriesOnly(renderUnboundNXDomain)},
+	"rpz": {contentType: echo.MIMETextPlainCharsetUTF8, render: func(v view) string {
+		return renderRPZ(v.entries, v.serial(), false)
+	}},
+	"rpz-redirect": {contentType: echo.MIMETextPlainCharsetUTF8, render: func(v view) string {
+		return renderRPZ(v.entries, v.serial(), true)
+	}},
+	"adblock": {contentType: echo.MIMETextPlainCharsetUTF8, render: func(v view) string {
+		return renderAdblock(v.entries, v.untilNextChange(maxAdblockExpires))
+	}},
+}
+
+// pathFormats are the output formats served by default at each path.
+var pathFormats = map[string]string{
+	"/":                 "domains",
+	"/hosts":            "hosts",
+	"/dnsmasq":          "dnsmasq",
+	"/unbound":          "unbound",
+	"/unbound-nxdomain": "unbound-nxdomain",
+	"/rpz":              "rpz",
+	"/rpz-redirect":     "rpz-redirect",
+	"/adblock":          "adblock",
+}
+
+// renderDomains renders one domain per line, the list format used by blocky.
+// Wildcards are expanded to the known subdomains.
+func renderDomains(entries []domain.HostsEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		for _, name := range entry.Names() {
+			b.WriteString(name + "\n")
+		}
+	}
+	return b.String()
+}
+
+// renderHosts renders "IP domain" lines in hosts file format, with a line for each IP address of the entry.
+// Wildcards are expanded to the known subdomains.
+func renderHosts(entries []domain.HostsEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		for _, name := range entry.Names() {
+			for _, ip := range entry.IPs() {
+				b.WriteString(ip.String() + " " + name + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// renderDnsmasq renders configuration lines for dnsmasq. Wildcards become "address=/domain/IP" lines,
+// with a line for each IP address of the entry, because an address always matches all subdomains.
+// Other names, including known subdomains, become "host-record=name,IP[,IPv6]" lines, which only match the name.
+func renderDnsmasq(entries []domain.HostsEntry) string {
+	var b uniqueLines
+	for _, entry := range entries {
+		if entry.IncludeSubdomains {
+			for _, ip := range entry.IPs() {
+				b.WriteLine("address=/" + entry.Domain + "/" + ip.String())
+			}
+			continue
+		}
+		var ips []string
+		for _, ip := range entry.IPs() {
+			ips = append(ips, ip.String())
+		}
+		for _, name := range entry.Names() {
+			b.WriteLine("host-record=" + name + "," + strings.Join(ips, ","))
+		}
+	}
+	return b.String()
+}
+
+// renderUnbound renders local data for Unbound answering with the IP addresses of each entry.
+// Wildcards become redirect zones answering for all subdomains; other names only answer for themselves.
+// Unbound rejects duplicate zones and data, so lines repeated by several blockers are only written once.
+func renderUnbound(entries []domain.HostsEntry) string {
+	var b uniqueLines
+	b.WriteString("server:\n")
+	for _, entry := range entries {
+		names := entry.Names()
+		if entry.IncludeSubdomains {
+			names = []string{entry.Domain}
+			b.WriteLine(fmt.Sprintf("local-zone: \"%s.\" redirect", entry.Domain))
+		}
+		for _, name := range names {
+			for _, ip := range entry.IPs() {
+				b.WriteLine(fmt.Sprintf("local-data: \"%s. %s %s\"", name, recordType(ip), ip))
+			}
+		}
+	}
+	return b.String()
+}
+
+// renderUnboundNXDomain renders local zones for Unbound answering NXDOMAIN for each entry.
+// A local zone always includes all subdomains, so known subdomains are not listed.
+func renderUnboundNXDomain(entries []domain.HostsEntry) string {
+	var b uniqueLines
+	b.WriteString("server:\n")
+	for _, entry := range entries {
+		b.WriteLine(fmt.Sprintf("local-zone: \"%s.\" always_nxdomain", entry.Domain))
+	}
+	return b.String()
+}
+
+// recordType returns the DNS record type of an address of ip.
+func recordType(ip net.IP) string {
+	if ip.To4() != nil {
+		return "A"
+	}
+	return "AAAA"
+}
+
+// uniqueLines is a strings.Builder that writes each line given to WriteLine only once.
+type uniqueLines struct {
+	strings.Builder
+	seen map[string]bool
+}
+
+func (b *uniqueLines) WriteLine(line string) {
+	if b.seen[line] {
+		return
+	}
+	if b.seen == nil {
+		b.seen = map[string]bool{}
+	}
+	b.seen[line] = true
+	b.WriteString(line + "\n")
+}
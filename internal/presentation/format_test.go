@@ -0,0 +1,120 @@
+package presentation
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func exampleEntries() []domain.HostsEntry {
+	return []domain.HostsEntry{
+		{
+			IP:                net.IPv4(0, 0, 0, 0),
+			Domain:            "twitter.com",
+			IncludeSubdomains: true,
+			Subdomains:        []string{"mobile.twitter.com"},
+		},
+		{
+			IP:     net.IPv4(0, 0, 0, 0),
+			IPv6:   net.IPv6unspecified,
+			Domain: "x.com",
+		},
+		{
+			IP:     net.ParseIP("192.0.2.1"),
+			Domain: "reddit.com",
+		},
+	}
+}
+
+func TestRenderDomains(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", renderDomains(nil))
+	assert.Equal(t, "twitter.com\n"+
+		"mobile.twitter.com\n"+
+		"x.com\n"+
+		"reddit.com\n", renderDomains(exampleEntries()))
+}
+
+func TestRenderHosts(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", renderHosts(nil))
+	assert.Equal(t, "0.0.0.0 twitter.com\n"+
+		"0.0.0.0 mobile.twitter.com\n"+
+		"0.0.0.0 x.com\n"+
+		":: x.com\n"+
+		"192.0.2.1 reddit.com\n", renderHosts(exampleEntries()))
+}
+
+func TestRenderDnsmasq(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", renderDnsmasq(nil))
+	assert.Equal(t, "address=/twitter.com/0.0.0.0\n"+
+		"host-record=x.com,0.0.0.0,::\n"+
+		"host-record=reddit.com,192.0.2.1\n", renderDnsmasq(exampleEntries()))
+
+	entries := []domain.HostsEntry{
+		{IP: net.IPv4(0, 0, 0, 0), Domain: "x.com", Subdomains: []string{"mobile.x.com"}},
+		{IP: net.IPv4(0, 0, 0, 0), Domain: "x.com", Subdomains: []string{"mobile.x.com"}},
+	}
+	assert.Equal(t, "host-record=x.com,0.0.0.0\n"+
+		"host-record=mobile.x.com,0.0.0.0\n", renderDnsmasq(entries),
+		"expected names without wildcard to only match themselves, written once")
+}
+
+func TestRenderUnbound(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "server:\n", renderUnbound(nil))
+	assert.Equal(t, "server:\n"+
+		"local-zone: \"twitter.com.\" redirect\n"+
+		"local-data: \"twitter.com. A 0.0.0.0\"\n"+
+		"local-data: \"x.com. A 0.0.0.0\"\n"+
+		"local-data: \"x.com. AAAA ::\"\n"+
+		"local-data: \"reddit.com. A 192.0.2.1\"\n", renderUnbound(exampleEntries()))
+
+	entries := []domain.HostsEntry{
+		{IP: net.IPv6unspecified, Domain: "x.com", Subdomains: []string{"mobile.x.com"}},
+	}
+	assert.Equal(t, "server:\n"+
+		"local-data: \"x.com. AAAA ::\"\n"+
+		"local-data: \"mobile.x.com. AAAA ::\"\n", renderUnbound(entries),
+		"expected known subdomains to be listed without a wildcard")
+
+	duplicated := append(exampleEntries()[:1], exampleEntries()[:1]...)
+	assert.Equal(t, "server:\n"+
+		"local-zone: \"twitter.com.\" redirect\n"+
+		"local-data: \"twitter.com. A 0.0.0.0\"\n", renderUnbound(duplicated),
+		"expected duplicated domains to be written once")
+}
+
+func TestRenderUnboundNXDomain(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "server:\n", renderUnboundNXDomain(nil))
+	assert.Equal(t, "server:\n"+
+		"local-zone: \"twitter.com.\" always_nxdomain\n"+
+		"local-zone: \"x.com.\" always_nxdomain\n"+
+		"local-zone: \"reddit.com.\" always_nxdomain\n", renderUnboundNXDomain(exampleEntries()))
+
+	duplicated := append(exampleEntries()[:1], exampleEntries()[:1]...)
+	assert.Equal(t, "server:\n"+
+		"local-zone: \"twitter.com.\" always_nxdomain\n", renderUnboundNXDomain(duplicated),
+		"expected duplicated domains to be written once")
+}
+
+func TestView_untilNextChange(t *testing.T) {
+	t.Parallel()
+
+	generator := domain.NewHostsGenerator(exampleBlockers())
+	monday := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+
+	v := view{at: monday, generator: generator}
+	assert.Equal(t, 2*time.Hour, v.untilNextChange(24*time.Hour), "expected the start of the lunch break")
+	assert.Equal(t, time.Hour, v.untilNextChange(time.Hour), "expected the maximum without a change before")
+}
@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultCalendarDays = 7
+	maxCalendarDays     = 366
+	icsTimeFormat       = "20060102T150405Z"
+	// icsLineLength is the longest content line in octets, without the line break.
+	icsLineLength = 75
+)
+
+// genCalendar renders the blocking schedule of the upcoming days as an iCalendar feed.
+// The number of days can be set with the "days" query parameter, and the start with the "at" query parameter.
+func (s *Server) genCalendar(c echo.Context) error {
+	days := defaultCalendarDays
+	if v := c.QueryParam("days"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > maxCalendarDays {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", maxCalendarDays))
+		}
+		days = d
+	}
+
+	now, _, err := requestTime(c)
+	if err != nil {
+		return err
+	}
+	// Windows are computed from the previous day, so that the start and UID of an ongoing window are stable.
+	schedule := s.loaded.Load().generator.Schedule(now.AddDate(0, 0, -1), now.AddDate(0, 0, days))
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderCalendar(schedule, now)))
+}
+
+// renderCalendar renders every window ending after now as a VEVENT (RFC 5545).
+func renderCalendar(schedule []domain.DomainWindows, now time.Time) string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(foldICS(fmt.Sprintf(format, args...)))
+	}
+	stamp := now.UTC().Format(icsTimeFormat)
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//sinkhole-detox//schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Sinkhole-Detox")
+	for _, d := range schedule {
+		for _, w := range d.Windows {
+			if !w.End.After(now) {
+				continue
+			}
+			line("BEGIN:VEVENT")
+			line("UID:%s-%d@sinkhole-detox", d.Domain, w.Start.Unix())
+			line("DTSTAMP:%s", stamp)
+			line("DTSTART:%s", w.Start.UTC().Format(icsTimeFormat))
+			line("DTEND:%s", w.End.UTC().Format(icsTimeFormat))
+			line("SUMMARY:%s blocked", d.Domain)
+			line("END:VEVENT")
+		}
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// foldICS terminates a content line with CRLF, folding it into lines of at most icsLineLength octets,
+// each continued line starting with a space (RFC 5545, section 3.1). UTF-8 sequences are not split.
+func foldICS(line string) string {
+	var b strings.Builder
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			// Not UTF-8, so octets are cut anywhere.
+			cut = limit
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The leading space counts toward the length of continued lines.
+		limit = icsLineLength - 1
+	}
+	b.WriteString(line + "\r\n")
+	return b.String()
+}
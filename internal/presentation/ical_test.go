@@ -0,0 +1,37 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldICS(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "SUMMARY:x.com blocked\r\n", foldICS("SUMMARY:x.com blocked"))
+	assert.Equal(t, strings.Repeat("a", 75)+"\r\n", foldICS(strings.Repeat("a", 75)))
+	assert.Equal(t, strings.Repeat("a", 75)+"\r\n "+strings.Repeat("b", 74)+"\r\n c\r\n",
+		foldICS(strings.Repeat("a", 75)+strings.Repeat("b", 74)+"c"))
+	assert.Equal(t, strings.Repeat("a", 74)+"\r\n é\r\n", foldICS(strings.Repeat("a", 74)+"é"),
+		"expected UTF-8 sequences not to be split")
+}
+
+func TestRenderCalendar_LongDomain(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + ".example.com"
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	schedule := []domain.DomainWindows{{Domain: long, Windows: []domain.Window{{Start: start, End: start.Add(time.Hour)}}}}
+
+	out := renderCalendar(schedule, start)
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(l), 75, "expected content lines to be folded: %q", l)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "\r\nUID:"+long+"-1736154000@sinkhole-detox\r\n")
+	assert.Contains(t, unfolded, "\r\nSUMMARY:"+long+" blocked\r\n")
+}
@@ -0,0 +1,105 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+// renderInterval is the longest time a rendered file is left unchecked,
+// so that changes not known in advance, such as edited calendar files, are picked up.
+const renderInterval = time.Minute
+
+// FormatNames returns the names of the output formats, sorted.
+func FormatNames() []string {
+	return slices.Sorted(maps.Keys(formats))
+}
+
+// Render renders the domains blocked by the blockers of generator at t in the named format,
+// as served by the "format" query parameter. loadedAt is the time the blockers were loaded,
+// which the serial of RPZ zones is at least.
+func Render(generator *domain.HostsGenerator, name string, t, loadedAt time.Time) (string, error) {
+	f, ok := formats[name]
+	if !ok {
+		return "", fmt.Errorf("unknown format: %s", name)
+	}
+	return f.render(fixedView(generator, loadedAt, nil)(t)), nil
+}
+
+// WatchRender renders like Render now, and again at every schedule change until ctx is done,
+// calling write whenever the result differs from the last one written. A failed write is retried
+// at the next check, at most renderInterval later.
+func WatchRender(ctx context.Context, generator *domain.HostsGenerator, name string, loadedAt time.Time, write func(string) error) error {
+	f, ok := formats[name]
+	if !ok {
+		return fmt.Errorf("unknown format: %s", name)
+	}
+	renderChanges(ctx, f, fixedView(generator, loadedAt, &serials{}), write)
+	return nil
+}
+
+func fixedView(generator *domain.HostsGenerator, loadedAt time.Time, serials *serials) func(t time.Time) view {
+	return func(t time.Time) view {
+		return view{at: t, entries: generator.Gen(t), generator: generator, loadedAt: loadedAt, serials: serials}
+	}
+}
+
+// renderChanges renders f with the view at the current time, now and at every schedule change until ctx is done,
+// and calls write if the result differs from the last one written successfully.
+func renderChanges(ctx context.Context, f format, view func(t time.Time) view, write func(string) error) {
+	var written string
+	first := true
+	for {
+		now := nowFunc()
+		v := view(now)
+		out := f.render(v)
+		if first || out != written {
+			if err := write(out); err == nil {
+				written, first = out, false
+			}
+		}
+
+		wait := renderInterval
+		if next := v.generator.NextChange(now, renderInterval); !next.IsZero() {
+			wait = next.Sub(now)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// WriteFileAtomic replaces the file at path with data, so that readers never see a partially written file.
+func WriteFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNames(t *testing.T) {
+	t.Parallel()
+
+	names := FormatNames()
+	assert.Len(t, names, len(formats))
+	assert.IsIncreasing(t, names)
+	for _, name := range pathFormats {
+		assert.Contains(t, names, name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	generator := domain.NewHostsGenerator(exampleBlockers())
+	monday := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+
+	out, err := Render(generator, "hosts", monday, monday.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0 twitter.com\n:: twitter.com\n0.0.0.0 mobile.twitter.com\n:: mobile.twitter.com\n", out)
+
+	out, err = Render(generator, "rpz", monday, monday.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Contains(t, out, "IN SOA localhost. hostmaster.localhost. 1736154000 ", "expected the serial of the last change")
+
+	_, err = Render(generator, "bind", monday, monday)
+	assert.Error(t, err)
+}
+
+func TestWatchRender(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // Monday
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(resetNowFunc)
+
+	generator := domain.NewHostsGenerator(exampleBlockers())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var written []string
+	err := WatchRender(ctx, generator, "domains", now, func(out string) error {
+		written = append(written, out)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"twitter.com\nmobile.twitter.com\n"}, written, "expected the list to be written once")
+
+	assert.Error(t, WatchRender(ctx, generator, "bind", now, func(string) error { return nil }))
+}
+
+func TestRenderChanges(t *testing.T) {
+	now := time.Date(2025, 1, 6, 11, 59, 59, 999_000_000, time.UTC) // Monday, a millisecond before the lunch break
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(resetNowFunc)
+
+	view := fixedView(domain.NewHostsGenerator(exampleBlockers()), now, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var written []string
+	calls := 0
+	renderChanges(ctx, formats["domains"], view, func(out string) error {
+		calls++
+		switch calls {
+		case 1:
+			// Fail the first write, so that it is retried at the change.
+			return errors.New("disk full")
+		case 2:
+			now = now.Add(time.Millisecond)
+		default:
+			cancel()
+		}
+		written = append(written, out)
+		return nil
+	})
+	assert.Equal(t, []string{"twitter.com\nmobile.twitter.com\n", ""}, written, "expected the empty list after the change")
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zone")
+
+	assert.NoError(t, WriteFileAtomic(path, []byte("first")))
+	assert.NoError(t, WriteFileAtomic(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "expected no temporary files to be left")
+
+	assert.Error(t, WriteFileAtomic(filepath.Join(dir, "missing", "zone"), []byte("data")))
+}
@@ -0,0 +1,101 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+)
+
+const (
+	// rpzTTL is the TTL of the records and the SOA timers of the zone in seconds,
+	// kept short so that servers pick up schedule changes quickly.
+	rpzTTL = 60
+	// rpzLookback is how far back the last schedule change is searched for the serial.
+	rpzLookback = 7 * 24 * time.Hour
+)
+
+// serial returns the SOA serial of the zone: the Unix time of the last schedule change,
+// or of the time the blockers were loaded if that is later.
+// It is at least the start of the day after the start of the lookback, which is later than any change that fell out
+// of the lookback, so the serial changes at most daily when the schedule does not.
+// If the view has serials, the serial never decreases and increases whenever the entries change.
+func (v view) serial() uint32 {
+	s := v.generator.LastChange(v.at, rpzLookback)
+	if floor := v.at.Add(-rpzLookback).Truncate(24 * time.Hour).Add(24 * time.Hour); s.Before(floor) {
+		s = floor
+	}
+	if s.Before(v.loadedAt) && !v.loadedAt.After(v.at) {
+		s = v.loadedAt
+	}
+	if v.serials == nil {
+		return uint32(s.Unix())
+	}
+	return v.serials.next(uint32(s.Unix()), v.entries)
+}
+
+// serials issues the SOA serials of a zone that is rendered again and again. The serial derived from the schedule
+// alone can decrease, e.g. when an event is added to a calendar file or a change leaves the lookback,
+// and stays the same when entries change without a schedule change, so the last serial issued is kept.
+type serials struct {
+	mu   sync.Mutex
+	last uint32
+	// tag identifies the entries the last serial was issued for.
+	tag string
+}
+
+// next returns the serial for entries, at least derived, never less than the last one and greater if the entries changed.
+func (s *serials) next(derived uint32, entries []domain.HostsEntry) uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag := etag("rpz", entries)
+	serial := max(s.last, derived)
+	if s.last != 0 && tag != s.tag && serial == s.last {
+		serial++
+	}
+	s.last, s.tag = serial, tag
+	return serial
+}
+
+// renderRPZ renders a DNS Response Policy Zone. Blocked names answer NXDOMAIN ("CNAME ."),
+// or the IP addresses of the entry if redirect is set. Wildcards are written as "*." records.
+func renderRPZ(entries []domain.HostsEntry, serial uint32, redirect bool) string {
+	var b uniqueLines
+	b.WriteString(fmt.Sprintf("$TTL %d\n", rpzTTL))
+	b.WriteString(fmt.Sprintf("@ IN SOA localhost. hostmaster.localhost. %d %d %d %d %d\n",
+		serial, rpzTTL, rpzTTL, 7*24*60*60, rpzTTL))
+	b.WriteString("@ IN NS localhost.\n")
+	for _, entry := range entries {
+		names := entry.Names()
+		if entry.IncludeSubdomains {
+			names = []string{entry.Domain, "*." + entry.Domain}
+		}
+		for _, name := range names {
+			if !redirect {
+				b.WriteLine(name + " CNAME .")
+				continue
+			}
+			for _, ip := range entry.IPs() {
+				b.WriteLine(fmt.Sprintf("%s %s %s", name, recordType(ip), ip))
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeZoneFile writes the RPZ zone to path now and at every schedule change until ctx is done.
+// The file is only replaced if the zone changed.
+func (s *Server) writeZoneFile(ctx context.Context, path string) {
+	renderChanges(ctx, formats["rpz"], s.view, func(zone string) error {
+		if err := WriteFileAtomic(path, []byte(zone)); err != nil {
+			slog.Error("failed to write zone file", "path", path, "error", err)
+			return err
+		}
+		slog.Info("zone file written", "path", path)
+		return nil
+	})
+}
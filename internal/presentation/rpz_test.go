@@ -0,0 +1,190 @@
+package presentation
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderRPZ(t *testing.T) {
+	t.Parallel()
+
+	header := "$TTL 60\n" +
+		"@ IN SOA localhost. hostmaster.localhost. 1736157600 60 60 604800 60\n" +
+		"@ IN NS localhost.\n"
+
+	assert.Equal(t, header, renderRPZ(nil, 1736157600, false))
+	assert.Equal(t, header+
+		"twitter.com CNAME .\n"+
+		"*.twitter.com CNAME .\n"+
+		"x.com CNAME .\n"+
+		"reddit.com CNAME .\n", renderRPZ(exampleEntries(), 1736157600, false))
+	assert.Equal(t, header+
+		"twitter.com A 0.0.0.0\n"+
+		"*.twitter.com A 0.0.0.0\n"+
+		"x.com A 0.0.0.0\n"+
+		"x.com AAAA ::\n"+
+		"reddit.com A 192.0.2.1\n", renderRPZ(exampleEntries(), 1736157600, true))
+
+	entries := []domain.HostsEntry{
+		{IP: exampleEntries()[0].IP, Domain: "x.com", Subdomains: []string{"mobile.x.com"}},
+		{IP: exampleEntries()[0].IP, Domain: "x.com", Subdomains: []string{"mobile.x.com"}},
+	}
+	assert.Equal(t, header+
+		"x.com CNAME .\n"+
+		"mobile.x.com CNAME .\n", renderRPZ(entries, 1736157600, false),
+		"expected known subdomains without wildcard and duplicates written once")
+}
+
+func TestView_serial(t *testing.T) {
+	t.Parallel()
+
+	generator := domain.NewHostsGenerator(exampleBlockers())
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time {
+		return monday.Add(time.Duration(day)*24*time.Hour + time.Duration(hour)*time.Hour)
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		loadedAt time.Time
+		expected time.Time
+	}{
+		{
+			name:     "should use the last schedule change",
+			at:       at(0, 10),
+			loadedAt: at(-30, 0),
+			expected: at(0, 9),
+		},
+		{
+			name:     "should use the load time if it is later",
+			at:       at(0, 10),
+			loadedAt: at(0, 9).Add(30 * time.Minute),
+			expected: at(0, 9).Add(30 * time.Minute),
+		},
+		{
+			name:     "should ignore a load time after the time",
+			at:       at(0, 10),
+			loadedAt: at(0, 11),
+			expected: at(0, 9),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := view{at: tt.at, generator: generator, loadedAt: tt.loadedAt}
+			assert.Equal(t, uint32(tt.expected.Unix()), v.serial())
+		})
+	}
+
+	v := view{at: at(16, 10), generator: domain.NewHostsGenerator(nil), loadedAt: at(-30, 0)}
+	assert.Equal(t, uint32(at(10, 0).Unix()), v.serial(), "expected the start of the day after the lookback without changes")
+}
+
+func TestView_serial_ChangeLeavingLookback(t *testing.T) {
+	t.Parallel()
+
+	end := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	generator := domain.NewHostsGenerator([]domain.Blocker{{
+		Domain:    "twitter.com",
+		ForwardTo: net.IPv4(0, 0, 0, 0),
+		Rules: []domain.BlockRule{
+			domain.DateRangeRule{Op: domain.BlockOpsBlock, Start: end.AddDate(0, 0, -1), End: end},
+		},
+	}})
+	loadedAt := end.AddDate(0, 0, -30)
+
+	before := view{at: end.Add(rpzLookback - time.Second), generator: generator, loadedAt: loadedAt}.serial()
+	after := view{at: end.Add(rpzLookback + time.Second), generator: generator, loadedAt: loadedAt}.serial()
+	assert.GreaterOrEqual(t, after, before, "expected the serial not to decrease when the last change leaves the lookback")
+}
+
+func TestView_serial_Monotonic(t *testing.T) {
+	t.Parallel()
+
+	generator := domain.NewHostsGenerator(exampleBlockers())
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	loadedAt := start.AddDate(0, 0, 3)
+
+	var last uint32
+	for at := start; at.Before(start.AddDate(0, 0, 21)); at = at.Add(17 * time.Minute) {
+		serial := view{at: at, generator: generator, loadedAt: loadedAt}.serial()
+		assert.GreaterOrEqual(t, serial, last, "serial decreased at %v", at)
+		last = serial
+	}
+}
+
+// mutableEvents is an event source whose events change, like an edited calendar file.
+type mutableEvents struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (m *mutableEvents) Events() []domain.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events
+}
+
+func (m *mutableEvents) set(events ...domain.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = events
+}
+
+func TestView_serial_EventsChanged(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, 1, 5, hour, min, 0, 0, time.UTC)
+	}
+	source := &mutableEvents{}
+	source.set(domain.Event{Start: at(9, 30), Duration: 10 * time.Minute})
+	generator := domain.NewHostsGenerator([]domain.Blocker{{
+		Domain:    "a.com",
+		ForwardTo: net.IPv4(0, 0, 0, 0),
+		Rules:     []domain.BlockRule{domain.EventRule{Op: domain.BlockOpsBlock, Source: source}},
+	}})
+	view := fixedView(generator, at(0, 0).AddDate(0, 0, -30), &serials{})
+
+	first := view(at(10, 0)).serial()
+	assert.Equal(t, uint32(at(9, 40).Unix()), first, "expected the end of the event")
+
+	// The added event started before the last change, so the schedule alone would give a lower serial.
+	source.set(domain.Event{Start: at(9, 30), Duration: 10 * time.Minute}, domain.Event{Start: at(9, 0), Duration: 2 * time.Hour})
+	v := view(at(10, 0))
+	assert.Len(t, v.entries, 1)
+	added := v.serial()
+	assert.Greater(t, added, first, "expected the serial to increase when a.com is blocked")
+	assert.Equal(t, added, view(at(10, 1)).serial(), "expected the serial to stay while the entries do")
+
+	source.set()
+	v = view(at(10, 2))
+	assert.Empty(t, v.entries)
+	assert.Greater(t, v.serial(), added, "expected the serial to increase when a.com is unblocked")
+}
+
+func TestServer_writeZoneFile(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // Monday
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(resetNowFunc)
+
+	path := filepath.Join(t.TempDir(), "sinkhole.rpz")
+	s := NewServer(exampleBlockers(), ServerConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.writeZoneFile(ctx, path)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, formats["rpz"].render(s.view(now)), string(data))
+	assert.Contains(t, string(data), "*.twitter.com CNAME .\n")
+}
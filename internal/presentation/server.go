@@ -1,8 +1,11 @@
 package presentation
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/alkshmir/sinkhole-detox/internal/domain"
 	"github.com/labstack/echo/v4"
@@ -11,44 +14,134 @@ import (
 
 type ServerConfig struct {
 	Port uint
+	// RPZFile is the path to keep the RPZ zone written to, if set.
+	RPZFile string
 }
 
 type Server struct {
-	e         *echo.Echo
-	config    ServerConfig
+	e      *echo.Echo
+	config ServerConfig
+	// ctx is canceled on Shutdown to stop the background work started by Start.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// loaded is replaced as a whole on reload, so that every request sees a consistent set of blockers.
+	loaded atomic.Pointer[loaded]
+	// serials are the serials of the RPZ zone served and written now, kept across reloads.
+	serials serials
+}
+
+// loaded are the blockers the server answers with.
+type loaded struct {
 	generator *domain.HostsGenerator
+	// at is the time the blockers were loaded.
+	at time.Time
+	// reloadErr is the error of the last failed attempt to replace the blockers, if any.
+	reloadErr error
 }
 
 func NewServer(b []domain.Blocker, conf ServerConfig) *Server {
-	generator := domain.NewHostsGenerator(b)
 	e := echo.New()
+	ctx, cancel := context.WithCancel(context.Background())
 	s := &Server{
-		e:         e,
-		config:    conf,
-		generator: generator,
+		e:      e,
+		config: conf,
+		ctx:    ctx,
+		cancel: cancel,
 	}
+	s.SetBlockers(b)
 
 	e.Use(middleware.Logger())
 
-	e.GET("/", s.genHosts)
+	for path := range pathFormats {
+		e.GET(path, s.genHosts)
+	}
+	e.GET("/calendar.ics", s.genCalendar)
+	e.GET("/status", s.genStatus)
 
 	return s
 }
 
-func (s *Server) Start() {
+// Start serves requests until the server fails or is shut down, in which case it returns http.ErrServerClosed.
+func (s *Server) Start() error {
 	port := s.config.Port
 	if port == 0 {
 		port = 8080
 	}
-	s.e.Logger.Fatal(s.e.Start(fmt.Sprintf(":%d", port)))
+	if s.config.RPZFile != "" {
+		go s.writeZoneFile(s.ctx, s.config.RPZFile)
+	}
+	return s.e.Start(fmt.Sprintf(":%d", port))
+}
+
+// Shutdown stops accepting requests and waits for the requests in progress to finish until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.cancel()
+	return s.e.Shutdown(ctx)
+}
+
+// SetBlockers replaces the blockers the server answers with. Requests in progress finish with the previous ones.
+func (s *Server) SetBlockers(b []domain.Blocker) {
+	s.loaded.Store(&loaded{generator: domain.NewHostsGenerator(b), at: nowFunc()})
+}
+
+// SetReloadError records that replacing the blockers failed, so that it is reported while the server
+// keeps answering with the current ones. It is cleared by the next SetBlockers.
+func (s *Server) SetReloadError(err error) {
+	for {
+		current := s.loaded.Load()
+		next := *current
+		next.reloadErr = err
+		if s.loaded.CompareAndSwap(current, &next) {
+			return
+		}
+	}
 }
 
+// genHosts renders the domains blocked now, or at the time of the "at" query parameter, in the format
+// of the "format" query parameter, defaulting to the format of the path.
+// Responses can be cached until the next schedule change and revalidated with their ETag.
 func (s *Server) genHosts(c echo.Context) error {
-	t := nowFunc()
-	entries := s.generator.Gen(t)
-	var response string
-	for _, entry := range entries {
-		response += entry.String() + "\n"
+	name := c.QueryParam("format")
+	if name == "" {
+		name = pathFormats[c.Path()]
+	}
+	f, ok := formats[name]
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown format: %s", name))
+	}
+
+	t, preview, err := requestTime(c)
+	if err != nil {
+		return err
+	}
+
+	v := s.view(t)
+	if preview {
+		// Previews do not advance the serial of the zone served now.
+		v.serials = nil
+	}
+	tag := etag(name, v.entries)
+	c.Response().Header().Set("ETag", tag)
+	if preview {
+		// Previews are not the current list, so they must not be served in place of it.
+		c.Response().Header().Set("Cache-Control", "no-store")
+	} else {
+		setCacheHeaders(c.Response().Header(), v.at, v.untilNextChange(maxCacheAge))
+	}
+	if match := c.Request().Header.Get("If-None-Match"); match != "" && etagMatches(match, tag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, f.contentType, []byte(f.render(v)))
+}
+
+// view evaluates the blockers at t.
+func (s *Server) view(t time.Time) view {
+	l := s.loaded.Load()
+	return view{
+		at:        t,
+		entries:   l.generator.Gen(t),
+		generator: l.generator,
+		loadedAt:  l.at,
+		serials:   &s.serials,
 	}
-	return c.String(http.StatusOK, response)
 }
@@ -0,0 +1,313 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func exampleBlockers() []domain.Blocker {
+	return []domain.Blocker{
+		{
+			Domain:            "twitter.com",
+			IncludeSubdomains: true,
+			Subdomains:        []string{"mobile.twitter.com"},
+			ForwardTo:         net.IPv4(0, 0, 0, 0),
+			ForwardToV6:       net.IPv6unspecified,
+			Rules: []domain.BlockRule{
+				domain.WeekdayRule{
+					Op:       domain.BlockOpsBlock,
+					From:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+					To:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+					Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				},
+				domain.EveryDayRule{
+					Op:   domain.BlockOpsAllow,
+					From: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+					To:   time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+}
+
+// serve sends a GET request for target to a server with exampleBlockers at now.
+func serve(t *testing.T, now time.Time, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveRequest(t, now, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+// serveRequest sends req to a server with exampleBlockers at now.
+func serveRequest(t *testing.T, now time.Time, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(resetNowFunc)
+
+	s := NewServer(exampleBlockers(), ServerConfig{})
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_genHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     time.Time
+		target   string
+		expected string
+	}{
+		{
+			name:     "should list blocked domains and their known subdomains",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/",
+			expected: "twitter.com\nmobile.twitter.com\n",
+		},
+		{
+			name:     "should return an empty list if nothing is blocked",
+			time:     time.Date(2025, 1, 6, 12, 30, 0, 0, time.UTC), // Monday
+			target:   "/",
+			expected: "",
+		},
+		{
+			name:     "should render hosts format at its path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/hosts",
+			expected: "0.0.0.0 twitter.com\n:: twitter.com\n0.0.0.0 mobile.twitter.com\n:: mobile.twitter.com\n",
+		},
+		{
+			name:     "should render the format of the query parameter",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/?format=hosts",
+			expected: "0.0.0.0 twitter.com\n:: twitter.com\n0.0.0.0 mobile.twitter.com\n:: mobile.twitter.com\n",
+		},
+		{
+			name:     "should render dnsmasq format at its path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/dnsmasq",
+			expected: "address=/twitter.com/0.0.0.0\naddress=/twitter.com/::\n",
+		},
+		{
+			name:     "should render unbound format at its path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/unbound",
+			expected: "server:\nlocal-zone: \"twitter.com.\" redirect\nlocal-data: \"twitter.com. A 0.0.0.0\"\nlocal-data: \"twitter.com. AAAA ::\"\n",
+		},
+		{
+			name:     "should render unbound nxdomain format at its path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/unbound-nxdomain",
+			expected: "server:\nlocal-zone: \"twitter.com.\" always_nxdomain\n",
+		},
+		{
+			name:   "should render rpz format at its path",
+			time:   time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target: "/rpz",
+			expected: "$TTL 60\n" +
+				"@ IN SOA localhost. hostmaster.localhost. 1736157600 60 60 604800 60\n" +
+				"@ IN NS localhost.\n" +
+				"twitter.com CNAME .\n" +
+				"*.twitter.com CNAME .\n",
+		},
+		{
+			name:     "should render adblock format at its path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/adblock",
+			expected: "! Title: Sinkhole-Detox\n! Expires: 2 hours\n||twitter.com^\n",
+		},
+		{
+			name:     "should list the domains blocked at the time of the at query parameter",
+			time:     time.Date(2025, 1, 4, 20, 0, 0, 0, time.UTC), // Saturday
+			target:   "/?at=2025-01-06T10:00:00Z",                  // Monday
+			expected: "twitter.com\nmobile.twitter.com\n",
+		},
+		{
+			name:     "should preview any format at the time of the at query parameter",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/dnsmasq?at=2025-01-04T21:30:00Z",           // Saturday
+			expected: "",
+		},
+		{
+			name:     "should prefer the query parameter over the path",
+			time:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			target:   "/hosts?format=domains",
+			expected: "twitter.com\nmobile.twitter.com\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.time, tt.target)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "text/plain; charset=UTF-8", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestServer_genHosts_Caching(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // Monday, blocked until 12:00
+
+	rec := serve(t, now, "/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	tag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+	assert.Equal(t, "max-age=3600", rec.Header().Get("Cache-Control"), "expected the maximum age before the next change")
+	assert.Equal(t, "Mon, 06 Jan 2025 11:00:00 GMT", rec.Header().Get("Expires"))
+
+	rec = serve(t, now.Add(90*time.Minute), "/")
+	assert.Equal(t, tag, rec.Header().Get("ETag"), "expected the same tag for the same domains")
+	assert.Equal(t, "max-age=1800", rec.Header().Get("Cache-Control"), "expected the time until the next change")
+	assert.Equal(t, "Mon, 06 Jan 2025 12:00:00 GMT", rec.Header().Get("Expires"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = serveRequest(t, now, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, tag, rec.Header().Get("ETag"))
+	assert.Equal(t, "max-age=3600", rec.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = serveRequest(t, time.Date(2025, 1, 6, 12, 30, 0, 0, time.UTC), req)
+	assert.Equal(t, http.StatusOK, rec.Code, "expected the list once the domains changed")
+	assert.NotEqual(t, tag, rec.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/hosts", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = serveRequest(t, now, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "expected different tags for different formats")
+
+	rec = serve(t, now, "/adblock")
+	tag = rec.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/adblock", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = serveRequest(t, now.Add(90*time.Minute), req)
+	assert.Equal(t, http.StatusNotModified, rec.Code, "expected the same tag although the expiry in the list changed")
+}
+
+func TestServer_genHosts_Preview(t *testing.T) {
+	rec := serve(t, time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "/?at=2025-01-06T12:30:00Z")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"), "expected previews not to be cached")
+	assert.Empty(t, rec.Header().Get("Expires"))
+
+	for _, at := range []string{"tomorrow", "2025-01-06", "2025-01-06T10:00:00"} {
+		t.Run(at, func(t *testing.T) {
+			rec := serve(t, time.Now(), "/?at="+at)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestServer_SetBlockers(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // Monday
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(resetNowFunc)
+
+	s := NewServer(exampleBlockers(), ServerConfig{})
+	get := func(target string) string {
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec.Body.String()
+	}
+	assert.Equal(t, "twitter.com\nmobile.twitter.com\n", get("/"))
+
+	blockers := exampleBlockers()
+	blockers[0].Domain = "x.com"
+	blockers[0].Subdomains = nil
+	s.SetBlockers(blockers)
+	assert.Equal(t, "x.com\n", get("/"), "expected the replaced blockers")
+
+	s.SetReloadError(errors.New("unknown schedule: focus"))
+	assert.Equal(t, "x.com\n", get("/"), "expected the current blockers to be kept")
+	assert.Contains(t, get("/status"), `"config_error":"unknown schedule: focus"`)
+
+	s.SetBlockers(exampleBlockers())
+	assert.NotContains(t, get("/status"), "config_error", "expected the error to be cleared")
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	assert.NoError(t, l.Close())
+
+	s := NewServer(exampleBlockers(), ServerConfig{Port: uint(port), RPZFile: filepath.Join(t.TempDir(), "sinkhole.rpz")})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.Start()
+	}()
+	assert.Eventually(t, func() bool {
+		res, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+		if err != nil {
+			return false
+		}
+		res.Body.Close()
+		return res.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, s.Shutdown(ctx))
+	assert.ErrorIs(t, <-errs, http.ErrServerClosed)
+	assert.ErrorIs(t, s.ctx.Err(), context.Canceled, "expected the zone file to stop being written")
+}
+
+func TestServer_genHosts_UnknownFormat(t *testing.T) {
+	rec := serve(t, time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "/?format=bind")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServer_genCalendar(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // Monday
+
+	rec := serve(t, now, "/calendar.ics?days=1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//sinkhole-detox//schedule//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"X-WR-CALNAME:Sinkhole-Detox\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:twitter.com-1736154000@sinkhole-detox\r\n"+
+		"DTSTAMP:20250106T100000Z\r\n"+
+		"DTSTART:20250106T090000Z\r\n"+
+		"DTEND:20250106T120000Z\r\n"+
+		"SUMMARY:twitter.com blocked\r\n"+
+		"END:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:twitter.com-1736168400@sinkhole-detox\r\n"+
+		"DTSTAMP:20250106T100000Z\r\n"+
+		"DTSTART:20250106T130000Z\r\n"+
+		"DTEND:20250106T180000Z\r\n"+
+		"SUMMARY:twitter.com blocked\r\n"+
+		"END:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:twitter.com-1736240400@sinkhole-detox\r\n"+
+		"DTSTAMP:20250106T100000Z\r\n"+
+		"DTSTART:20250107T090000Z\r\n"+
+		"DTEND:20250107T100000Z\r\n"+
+		"SUMMARY:twitter.com blocked\r\n"+
+		"END:VEVENT\r\n"+
+		"END:VCALENDAR\r\n", rec.Body.String())
+}
+
+func TestServer_genCalendar_InvalidDays(t *testing.T) {
+	for _, days := range []string{"0", "367", "abc"} {
+		t.Run(days, func(t *testing.T) {
+			rec := serve(t, time.Now(), "/calendar.ics?days="+days)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package presentation
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alkshmir/sinkhole-detox/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// statusHorizon is how far ahead the next change of a blocker is searched.
+const statusHorizon = 7 * 24 * time.Hour
+
+type statusResponse struct {
+	Time     time.Time       `json:"time"`
+	Blockers []blockerStatus `json:"blockers"`
+	// ConfigError is why the configuration could not be reloaded, if the last attempt failed.
+	ConfigError string `json:"config_error,omitempty"`
+}
+
+type blockerStatus struct {
+	Domain            string   `json:"domain"`
+	IncludeSubdomains bool     `json:"include_subdomains"`
+	Subdomains        []string `json:"subdomains,omitempty"`
+	Blocked           bool     `json:"blocked"`
+	// Rules are the active rules that decided the state.
+	Rules       []ruleStatus `json:"rules"`
+	ForwardTo   string       `json:"forward_to"`
+	ForwardToV6 string       `json:"forward_to_v6,omitempty"`
+	// NextChange is the time the state changes next, nil if it does not change within statusHorizon.
+	NextChange *time.Time `json:"next_change"`
+}
+
+type ruleStatus struct {
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	Ops   string `json:"ops"`
+}
+
+// genStatus describes for every blocker whether it blocks now, or at the time of the "at" query parameter,
+// why, and when that changes.
+func (s *Server) genStatus(c echo.Context) error {
+	t, _, err := requestTime(c)
+	if err != nil {
+		return err
+	}
+	l := s.loaded.Load()
+	res := status(l.generator.Blockers(), t)
+	if l.reloadErr != nil {
+		res.ConfigError = l.reloadErr.Error()
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func status(blockers []domain.Blocker, t time.Time) statusResponse {
+	res := statusResponse{Time: t, Blockers: []blockerStatus{}}
+	for _, b := range blockers {
+		e := b.Explain(t)
+		bs := blockerStatus{
+			Domain:            b.Domain,
+			IncludeSubdomains: b.IncludeSubdomains,
+			Subdomains:        b.Subdomains,
+			Blocked:           e.Blocked,
+			Rules:             []ruleStatus{},
+			ForwardTo:         b.ForwardTo.String(),
+		}
+		if b.ForwardToV6 != nil {
+			bs.ForwardToV6 = b.ForwardToV6.String()
+		}
+		for _, m := range e.Rules {
+			bs.Rules = append(bs.Rules, ruleStatus{Index: m.Index, Type: m.Type, Ops: string(m.Op)})
+		}
+		if next := b.NextChange(t, statusHorizon); !next.IsZero() {
+			bs.NextChange = &next
+		}
+		res.Blockers = append(res.Blockers, bs)
+	}
+	return res
+}
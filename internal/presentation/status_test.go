@@ -0,0 +1,73 @@
+package presentation
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_genStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected string
+	}{
+		{
+			name: "should report the block rule and the next change",
+			time: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			expected: `{"time":"2025-01-06T10:00:00Z","blockers":[{` +
+				`"domain":"twitter.com","include_subdomains":true,"subdomains":["mobile.twitter.com"],` +
+				`"blocked":true,"rules":[{"index":0,"type":"weekday","ops":"block"}],` +
+				`"forward_to":"0.0.0.0","forward_to_v6":"::","next_change":"2025-01-06T12:00:00Z"}]}` + "\n",
+		},
+		{
+			name: "should report the allow rule overriding the block rule",
+			time: time.Date(2025, 1, 6, 12, 30, 0, 0, time.UTC), // Monday
+			expected: `{"time":"2025-01-06T12:30:00Z","blockers":[{` +
+				`"domain":"twitter.com","include_subdomains":true,"subdomains":["mobile.twitter.com"],` +
+				`"blocked":false,"rules":[{"index":1,"type":"everyday","ops":"allow"}],` +
+				`"forward_to":"0.0.0.0","forward_to_v6":"::","next_change":"2025-01-06T13:00:00Z"}]}` + "\n",
+		},
+		{
+			name: "should report no rules if none is active",
+			time: time.Date(2025, 1, 4, 20, 0, 0, 0, time.UTC), // Saturday
+			expected: `{"time":"2025-01-04T20:00:00Z","blockers":[{` +
+				`"domain":"twitter.com","include_subdomains":true,"subdomains":["mobile.twitter.com"],` +
+				`"blocked":false,"rules":[],` +
+				`"forward_to":"0.0.0.0","forward_to_v6":"::","next_change":"2025-01-06T09:00:00Z"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.time, "/status")
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestServer_genStatus_Preview(t *testing.T) {
+	rec := serve(t, time.Date(2025, 1, 4, 20, 0, 0, 0, time.UTC), "/status?at=2025-01-06T10:00:00Z")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"time":"2025-01-06T10:00:00Z"`)
+	assert.Contains(t, rec.Body.String(), `"blocked":true`)
+
+	rec = serve(t, time.Now(), "/status?at=now")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	res := status(nil, time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, []blockerStatus{}, res.Blockers, "expected an empty list without blockers")
+
+	blockers := exampleBlockers()
+	blockers[0].Rules = nil
+	res = status(blockers, time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC))
+	assert.Nil(t, res.Blockers[0].NextChange, "expected no next change without rules")
+}
@@ -1,6 +1,11 @@
 package presentation
 
-import "time"
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
 
 // nowFunc is a function that returns the current time.
 // Modelled as a global variable to allow for easy mocking in tests.
@@ -13,3 +18,17 @@ func resetNowFunc() {
 func init() {
 	resetNowFunc()
 }
+
+// requestTime returns the time to evaluate the schedule at for a request:
+// the RFC 3339 time of the "at" query parameter to preview the schedule, or now.
+func requestTime(c echo.Context) (t time.Time, preview bool, err error) {
+	v := c.QueryParam("at")
+	if v == "" {
+		return nowFunc(), false, nil
+	}
+	t, err = time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false, echo.NewHTTPError(http.StatusBadRequest, "at must be an RFC 3339 time such as 2025-01-04T21:30:00+09:00")
+	}
+	return t, true, nil
+}